@@ -6,6 +6,7 @@ import (
 	"github.com/KainoaGardner/csc/internal/api"
 	"github.com/KainoaGardner/csc/internal/config"
 	"github.com/KainoaGardner/csc/internal/db"
+	"github.com/KainoaGardner/csc/internal/store"
 	"log"
 	"time"
 )
@@ -16,18 +17,34 @@ func main() {
 	context, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
-	client, err := db.ConnectToDB(context, config.DB)
+	store, disconnect, err := setupStore(context, config)
 	if err != nil {
 		log.Fatal(err)
 	}
-	defer func() {
-		if err := client.Disconnect(context); err != nil {
-			log.Fatal(err)
-		}
-	}()
+	defer disconnect()
 
 	server := api.NewAPIServer(fmt.Sprintf("%s:%s", config.PublicHost, config.Port))
-	if err := server.Run(client, config); err != nil {
+	if err := server.Run(store, config); err != nil {
 		log.Fatal(err)
 	}
 }
+
+func setupStore(ctx context.Context, storeConfig config.Config) (store.Store, func(), error) {
+	if storeConfig.Storage == config.MemoryStorage {
+		log.Println("Using in memory storage")
+		return store.NewMemoryStore(), func() {}, nil
+	}
+
+	client, err := db.ConnectToDB(ctx, storeConfig.DB)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	disconnect := func() {
+		if err := client.Disconnect(ctx); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	return store.NewMongoStore(client, storeConfig.DB), disconnect, nil
+}
@@ -6,9 +6,10 @@ import (
 	"github.com/go-chi/cors"
 
 	"github.com/KainoaGardner/csc/internal/config"
-	"go.mongodb.org/mongo-driver/mongo"
 
 	"github.com/KainoaGardner/csc/internal/engine"
+	"github.com/KainoaGardner/csc/internal/matchmaking"
+	"github.com/KainoaGardner/csc/internal/store"
 	"github.com/KainoaGardner/csc/internal/websockets"
 	"log"
 	"net/http"
@@ -25,7 +26,7 @@ func NewAPIServer(addr string) *APIServer {
 	}
 }
 
-func (s *APIServer) Run(client *mongo.Client, config config.Config) error {
+func (s *APIServer) Run(store store.Store, config config.Config) error {
 	r := chi.NewRouter()
 
 	r.Use(middleware.Logger)
@@ -39,12 +40,21 @@ func (s *APIServer) Run(client *mongo.Client, config config.Config) error {
 	}))
 
 	r.Route("/", func(r chi.Router) {
-		handHandler := NewHandler(client, config)
+		handHandler := NewHandler(store, config)
 		handHandler.RegisterRoutes(r)
 
 	})
 
-	engine.StartGlobalTimeCheck(5*time.Second, client, config, websockets.GameOver)
+	err := engine.StartClockScheduler(store, config, websockets.GameOver)
+	if err != nil {
+		return err
+	}
+	websockets.StartClockTicker(time.Second)
+	err = websockets.StartBotMoves(store, config)
+	if err != nil {
+		return err
+	}
+	matchmaking.StartPairing(time.Second, store, config)
 
 	log.Println("Listening on", s.addr)
 	return http.ListenAndServe(s.addr, r)
@@ -2,10 +2,10 @@ package api
 
 import (
 	"github.com/KainoaGardner/csc/internal/auth"
-	"github.com/KainoaGardner/csc/internal/db"
 	"github.com/KainoaGardner/csc/internal/engine"
 	"github.com/KainoaGardner/csc/internal/types"
 	"github.com/KainoaGardner/csc/internal/utils"
+	"github.com/KainoaGardner/csc/internal/websockets"
 	"github.com/go-chi/chi/v5"
 
 	"fmt"
@@ -23,25 +23,30 @@ func (h *Handler) registerGameRoutes(r chi.Router) {
 
 	r.Get("/game/{gameID}/private", h.getPrivateGame)
 	r.Get("/game/{gameID}", h.getBoard)
+	r.Get("/game/{gameID}/moves", h.getLegalMoves)
 	r.Post("/game/{gameID}/move", h.postMovePiece)
 	r.Post("/game/{gameID}/place", h.postPlacePiece)
 	r.Delete("/game/{gameID}/place", h.deletePlacePiece)
+	r.Post("/game/{gameID}/army", h.postArmy)
+	r.Post("/game/army", h.postProposeArmy)
+	r.Get("/game/{gameID}/prices", h.getPrices)
 
 	r.Post("/game/{gameID}/state", h.postState)
 
 	r.Post("/game/{gameID}/ready", h.postReady)
 	r.Post("/game/{gameID}/draw", h.postDraw)
+	r.Post("/game/{gameID}/takeback", h.postTakeback)
 }
 
 // admin
 func (h *Handler) getAllGames(w http.ResponseWriter, r *http.Request) {
-	statusCode, err := auth.CheckAdminRequest(h.client, h.config.DB, h.config.JWT.AccessKey, r)
+	statusCode, err := auth.CheckAdminRequest(h.store, h.config.JWT.AccessKey, r)
 	if err != nil {
 		utils.WriteError(w, statusCode, err)
 		return
 	}
 
-	games, err := db.ListAllGames(h.client, h.config.DB)
+	games, err := h.store.ListAllGames()
 	if err != nil {
 		utils.WriteError(w, http.StatusBadRequest, err)
 		return
@@ -60,11 +65,15 @@ func (h *Handler) getAllGames(w http.ResponseWriter, r *http.Request) {
 		gameResponse.Reason = game.Reason
 		gameResponse.State = game.State
 		gameResponse.Time = game.Time
+		gameResponse.TimeControl = game.TimeControl
+		gameResponse.Periods = game.Periods
 		gameResponse.LastMoveTime = game.LastMoveTime
 		gameResponse.Money = game.Money
 		gameResponse.Ready = game.Ready
 		gameResponse.Draw = game.Draw
+		gameResponse.Takeback = game.Takeback
 		gameResponse.Public = game.Public
+		gameResponse.Rules = engine.GetRules(*game)
 
 		result = append(result, gameResponse)
 	}
@@ -74,13 +83,13 @@ func (h *Handler) getAllGames(w http.ResponseWriter, r *http.Request) {
 
 // admin
 func (h *Handler) deleteAllGames(w http.ResponseWriter, r *http.Request) {
-	statusCode, err := auth.CheckAdminRequest(h.client, h.config.DB, h.config.JWT.AccessKey, r)
+	statusCode, err := auth.CheckAdminRequest(h.store, h.config.JWT.AccessKey, r)
 	if err != nil {
 		utils.WriteError(w, statusCode, err)
 		return
 	}
 
-	amount, err := db.DeleteAllGames(h.client, h.config.DB)
+	amount, err := h.store.DeleteAllGames()
 	if err != nil {
 		utils.WriteError(w, http.StatusBadRequest, err)
 		return
@@ -93,7 +102,7 @@ func (h *Handler) deleteAllGames(w http.ResponseWriter, r *http.Request) {
 
 // auth
 func (h *Handler) postCreateGame(w http.ResponseWriter, r *http.Request) {
-	claims, statusCode, err := auth.CheckValidAuth(h.client, h.config.DB, h.config.JWT.AccessKey, r)
+	claims, statusCode, err := auth.CheckValidAuth(h.store, h.config.JWT.AccessKey, r)
 	if err != nil {
 		utils.WriteError(w, statusCode, err)
 		return
@@ -112,22 +121,26 @@ func (h *Handler) postCreateGame(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	gameID, err := db.CreateGame(h.client, h.config.DB, game)
+	gameID, err := h.store.CreateGame(game)
 	if err != nil {
 		utils.WriteError(w, http.StatusBadRequest, err)
 		return
 	}
 
 	data := types.PostGameResponse{
-		ID:        gameID,
-		WhiteID:   game.WhiteID,
-		BlackID:   game.BlackID,
-		Color:     "w",
-		Width:     game.Board.Width,
-		Height:    game.Board.Height,
-		Money:     game.Money,
-		PlaceLine: game.Board.PlaceLine,
-		State:     game.State,
+		ID:          gameID,
+		WhiteID:     game.WhiteID,
+		BlackID:     game.BlackID,
+		Color:       "w",
+		Width:       game.Board.Width,
+		Height:      game.Board.Height,
+		Money:       game.Money,
+		StartTime:   game.Time,
+		TimeControl: game.TimeControl,
+		PlaceLine:   game.Board.PlaceLine,
+		State:       game.State,
+		Prices:      engine.GetPrices(*game),
+		Rules:       engine.GetRules(*game),
 	}
 
 	utils.WriteResponse(w, http.StatusOK, fmt.Sprintf("Game created"), data)
@@ -135,14 +148,14 @@ func (h *Handler) postCreateGame(w http.ResponseWriter, r *http.Request) {
 
 // auth
 func (h *Handler) postJoinGame(w http.ResponseWriter, r *http.Request) {
-	claims, statusCode, err := auth.CheckValidAuth(h.client, h.config.DB, h.config.JWT.AccessKey, r)
+	claims, statusCode, err := auth.CheckValidAuth(h.store, h.config.JWT.AccessKey, r)
 	if err != nil {
 		utils.WriteError(w, statusCode, err)
 		return
 	}
 
 	gameID := chi.URLParam(r, "gameID")
-	game, err := db.FindGame(h.client, h.config.DB, gameID)
+	game, err := h.store.FindGame(gameID)
 	if err != nil {
 		utils.WriteError(w, http.StatusBadRequest, err)
 		return
@@ -154,22 +167,26 @@ func (h *Handler) postJoinGame(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err = db.GameMoveUpdate(h.client, h.config.DB, gameID, *game)
+	err = h.store.GameMoveUpdate(gameID, *game)
 	if err != nil {
 		utils.WriteError(w, http.StatusBadRequest, err)
 		return
 	}
 
 	data := types.PostGameResponse{
-		ID:        gameID,
-		WhiteID:   game.WhiteID,
-		BlackID:   game.BlackID,
-		Color:     "w",
-		Width:     game.Board.Width,
-		Height:    game.Board.Height,
-		Money:     game.Money,
-		PlaceLine: game.Board.PlaceLine,
-		State:     game.State,
+		ID:          gameID,
+		WhiteID:     game.WhiteID,
+		BlackID:     game.BlackID,
+		Color:       "w",
+		Width:       game.Board.Width,
+		Height:      game.Board.Height,
+		Money:       game.Money,
+		StartTime:   game.Time,
+		TimeControl: game.TimeControl,
+		PlaceLine:   game.Board.PlaceLine,
+		State:       game.State,
+		Prices:      engine.GetPrices(*game),
+		Rules:       engine.GetRules(*game),
 	}
 
 	utils.WriteResponse(w, http.StatusOK, fmt.Sprintf("Joined"), data)
@@ -177,14 +194,14 @@ func (h *Handler) postJoinGame(w http.ResponseWriter, r *http.Request) {
 
 // auth either player
 func (h *Handler) getBoard(w http.ResponseWriter, r *http.Request) {
-	claims, statusCode, err := auth.CheckValidAuth(h.client, h.config.DB, h.config.JWT.AccessKey, r)
+	claims, statusCode, err := auth.CheckValidAuth(h.store, h.config.JWT.AccessKey, r)
 	if err != nil {
 		utils.WriteError(w, statusCode, err)
 		return
 	}
 
 	gameID := chi.URLParam(r, "gameID")
-	game, err := db.FindGame(h.client, h.config.DB, gameID)
+	game, err := h.store.FindGame(gameID)
 	if err != nil {
 		utils.WriteError(w, http.StatusBadRequest, err)
 		return
@@ -210,9 +227,33 @@ func (h *Handler) getBoard(w http.ResponseWriter, r *http.Request) {
 	utils.WriteResponse(w, http.StatusOK, "Board", data)
 }
 
+// auth either player
+func (h *Handler) getLegalMoves(w http.ResponseWriter, r *http.Request) {
+	claims, statusCode, err := auth.CheckValidAuth(h.store, h.config.JWT.AccessKey, r)
+	if err != nil {
+		utils.WriteError(w, statusCode, err)
+		return
+	}
+
+	gameID := chi.URLParam(r, "gameID")
+	game, moves, err := engine.LegalMovesCase(gameID, claims.UserID, h.store)
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	data := types.LegalMovesResponse{
+		ID:    game.ID,
+		Turn:  game.Turn,
+		Moves: moves,
+	}
+
+	utils.WriteResponse(w, http.StatusOK, fmt.Sprintf("%d legal moves", len(moves)), data)
+}
+
 // auth either player
 func (h *Handler) postMovePiece(w http.ResponseWriter, r *http.Request) {
-	claims, statusCode, err := auth.CheckValidAuth(h.client, h.config.DB, h.config.JWT.AccessKey, r)
+	claims, statusCode, err := auth.CheckValidAuth(h.store, h.config.JWT.AccessKey, r)
 	if err != nil {
 		utils.WriteError(w, statusCode, err)
 		return
@@ -226,7 +267,7 @@ func (h *Handler) postMovePiece(w http.ResponseWriter, r *http.Request) {
 	}
 
 	gameID := chi.URLParam(r, "gameID")
-	game, err := db.FindGame(h.client, h.config.DB, gameID)
+	game, err := h.store.FindGame(gameID)
 	if err != nil {
 		utils.WriteError(w, http.StatusBadRequest, err)
 		return
@@ -256,7 +297,7 @@ func (h *Handler) postMovePiece(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err = db.GameMoveUpdate(h.client, h.config.DB, gameID, *game)
+	err = h.store.GameMoveUpdate(gameID, *game)
 	if err != nil {
 		utils.WriteError(w, http.StatusBadRequest, err)
 		return
@@ -268,26 +309,26 @@ func (h *Handler) postMovePiece(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err = db.GameLogUpdate(h.client, h.config.DB, gameID, postMove.Move, fen)
+	err = h.store.GameLogUpdate(gameID, postMove.Move, fen)
 	if err != nil {
 		utils.WriteError(w, http.StatusBadRequest, err)
 		return
 	}
 
 	if game.State == types.OverState {
-		gameLog, err := db.FindGameLogFromGameID(h.client, h.config.DB, gameID)
+		gameLog, err := h.store.FindGameLogFromGameID(gameID)
 		if err != nil {
 			utils.WriteError(w, http.StatusBadRequest, err)
 			return
 		}
 		engine.SetupFinalGameLog(*game, gameLog)
-		err = db.GameLogFinalUpdate(h.client, h.config.DB, gameID, *gameLog)
+		err = h.store.GameLogFinalUpdate(gameID, *gameLog)
 		if err != nil {
 			utils.WriteError(w, http.StatusBadRequest, err)
 			return
 		}
 
-		_, err = db.DeleteGame(h.client, h.config.DB, gameID)
+		_, err = h.store.DeleteGame(gameID)
 		if err != nil {
 			utils.WriteError(w, http.StatusBadRequest, err)
 			return
@@ -303,23 +344,26 @@ func (h *Handler) postMovePiece(w http.ResponseWriter, r *http.Request) {
 			Reason:        game.Reason,
 			State:         game.State,
 			LastMoveTime:  game.LastMoveTime,
+			Clock:         engine.GetClock(*game),
 		}
 
 		utils.WriteResponse(w, http.StatusOK, "Game Over", data)
 	} else {
 		data := types.PostMoveResponse{
-			ID:   game.ID,
-			FEN:  fen,
-			Move: postMove.Move,
+			ID:    game.ID,
+			FEN:   fen,
+			Move:  postMove.Move,
+			Clock: engine.GetClock(*game),
 		}
 		utils.WriteResponse(w, http.StatusOK, "Piece moved", data)
+		websockets.ScheduleBotMove(gameID, h.store, h.config)
 	}
 
 }
 
 // auth either player
 func (h *Handler) postPlacePiece(w http.ResponseWriter, r *http.Request) {
-	claims, statusCode, err := auth.CheckValidAuth(h.client, h.config.DB, h.config.JWT.AccessKey, r)
+	claims, statusCode, err := auth.CheckValidAuth(h.store, h.config.JWT.AccessKey, r)
 	if err != nil {
 		utils.WriteError(w, statusCode, err)
 		return
@@ -333,7 +377,7 @@ func (h *Handler) postPlacePiece(w http.ResponseWriter, r *http.Request) {
 	}
 
 	gameID := chi.URLParam(r, "gameID")
-	game, err := db.FindGame(h.client, h.config.DB, gameID)
+	game, err := h.store.FindGame(gameID)
 	if err != nil {
 		utils.WriteError(w, http.StatusBadRequest, err)
 		return
@@ -357,7 +401,7 @@ func (h *Handler) postPlacePiece(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err = db.GamePlaceUpdate(h.client, h.config.DB, gameID, place, *game)
+	err = h.store.GamePlaceUpdate(gameID, *game)
 	if err != nil {
 		utils.WriteError(w, http.StatusBadRequest, err)
 		return
@@ -383,7 +427,7 @@ func (h *Handler) postPlacePiece(w http.ResponseWriter, r *http.Request) {
 
 // auth either player
 func (h *Handler) deletePlacePiece(w http.ResponseWriter, r *http.Request) {
-	claims, statusCode, err := auth.CheckValidAuth(h.client, h.config.DB, h.config.JWT.AccessKey, r)
+	claims, statusCode, err := auth.CheckValidAuth(h.store, h.config.JWT.AccessKey, r)
 	if err != nil {
 		utils.WriteError(w, statusCode, err)
 		return
@@ -397,7 +441,7 @@ func (h *Handler) deletePlacePiece(w http.ResponseWriter, r *http.Request) {
 	}
 
 	gameID := chi.URLParam(r, "gameID")
-	game, err := db.FindGame(h.client, h.config.DB, gameID)
+	game, err := h.store.FindGame(gameID)
 	if err != nil {
 		utils.WriteError(w, http.StatusBadRequest, err)
 		return
@@ -421,7 +465,7 @@ func (h *Handler) deletePlacePiece(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err = db.GamePlaceUpdate(h.client, h.config.DB, gameID, place, *game)
+	err = h.store.GamePlaceUpdate(gameID, *game)
 	if err != nil {
 		utils.WriteError(w, http.StatusBadRequest, err)
 		return
@@ -445,9 +489,71 @@ func (h *Handler) deletePlacePiece(w http.ResponseWriter, r *http.Request) {
 	utils.WriteResponse(w, http.StatusOK, "Piece deleted", data)
 }
 
+// auth either player
+func (h *Handler) postArmy(w http.ResponseWriter, r *http.Request) {
+	claims, statusCode, err := auth.CheckValidAuth(h.store, h.config.JWT.AccessKey, r)
+	if err != nil {
+		utils.WriteError(w, statusCode, err)
+		return
+	}
+
+	var postArmy types.PostArmy
+	err = utils.ParseJSON(r, &postArmy)
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	gameID := chi.URLParam(r, "gameID")
+	_, data, err := engine.ArmyCase(gameID, claims.UserID, postArmy, h.store)
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	if data.Placed {
+		utils.WriteResponse(w, http.StatusOK, fmt.Sprintf("%d pieces placed", len(data.Pieces)), data)
+	} else {
+		utils.WriteResponse(w, http.StatusOK, fmt.Sprintf("%d pieces proposed", len(data.Pieces)), data)
+	}
+}
+
+func (h *Handler) postProposeArmy(w http.ResponseWriter, r *http.Request) {
+	var proposal types.PostArmyProposal
+	err := utils.ParseJSON(r, &proposal)
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	data, err := engine.ProposeArmyForBoard(proposal)
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	utils.WriteResponse(w, http.StatusOK, fmt.Sprintf("%d pieces proposed", len(data.Pieces)), data)
+}
+
+func (h *Handler) getPrices(w http.ResponseWriter, r *http.Request) {
+	gameID := chi.URLParam(r, "gameID")
+	game, err := h.store.FindGame(gameID)
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	data := types.PricesResponse{
+		ID:     game.ID,
+		Prices: engine.GetPrices(*game),
+	}
+
+	utils.WriteResponse(w, http.StatusOK, fmt.Sprintf("%d pieces for sale", len(data.Prices)), data)
+}
+
 // admin
 func (h *Handler) postState(w http.ResponseWriter, r *http.Request) {
-	statusCode, err := auth.CheckAdminRequest(h.client, h.config.DB, h.config.JWT.AccessKey, r)
+	statusCode, err := auth.CheckAdminRequest(h.store, h.config.JWT.AccessKey, r)
 	if err != nil {
 		utils.WriteError(w, statusCode, err)
 		return
@@ -461,7 +567,7 @@ func (h *Handler) postState(w http.ResponseWriter, r *http.Request) {
 	}
 
 	gameID := chi.URLParam(r, "gameID")
-	game, err := db.FindGame(h.client, h.config.DB, gameID)
+	game, err := h.store.FindGame(gameID)
 	if err != nil {
 		utils.WriteError(w, http.StatusBadRequest, err)
 		return
@@ -469,7 +575,7 @@ func (h *Handler) postState(w http.ResponseWriter, r *http.Request) {
 
 	game.State = postState.State
 
-	err = db.GameStateUpdate(h.client, h.config.DB, gameID, *game)
+	err = h.store.GameStateUpdate(gameID, *game)
 	if err != nil {
 		utils.WriteError(w, http.StatusBadRequest, err)
 		return
@@ -484,7 +590,7 @@ func (h *Handler) postState(w http.ResponseWriter, r *http.Request) {
 
 // auth either player
 func (h *Handler) postReady(w http.ResponseWriter, r *http.Request) {
-	claims, statusCode, err := auth.CheckValidAuth(h.client, h.config.DB, h.config.JWT.AccessKey, r)
+	claims, statusCode, err := auth.CheckValidAuth(h.store, h.config.JWT.AccessKey, r)
 	if err != nil {
 		utils.WriteError(w, statusCode, err)
 		return
@@ -498,7 +604,7 @@ func (h *Handler) postReady(w http.ResponseWriter, r *http.Request) {
 	}
 
 	gameID := chi.URLParam(r, "gameID")
-	game, err := db.FindGame(h.client, h.config.DB, gameID)
+	game, err := h.store.FindGame(gameID)
 	if err != nil {
 		utils.WriteError(w, http.StatusBadRequest, err)
 		return
@@ -516,15 +622,21 @@ func (h *Handler) postReady(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err = db.GameReadyUpdate(h.client, h.config.DB, gameID, *game)
+	err = h.store.GameReadyUpdate(gameID, *game)
 	if err != nil {
 		utils.WriteError(w, http.StatusBadRequest, err)
 		return
 	}
 
 	if game.State == types.MoveState {
-		gameLog := engine.SetupGameLog(*game)
-		gameLogID, err := db.CreateGameLog(h.client, h.config.DB, gameLog)
+		fen, err := engine.ConvertBoardToString(*game)
+		if err != nil {
+			utils.WriteError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		gameLog := engine.SetupGameLog(*game, fen)
+		gameLogID, err := h.store.CreateGameLog(gameLog)
 		if err != nil {
 			utils.WriteError(w, http.StatusBadRequest, err)
 			return
@@ -536,6 +648,7 @@ func (h *Handler) postReady(w http.ResponseWriter, r *http.Request) {
 			"gameLogID": gameLogID,
 		}
 		utils.WriteResponse(w, http.StatusOK, "Game Start", data)
+		websockets.ScheduleBotMove(gameID, h.store, h.config)
 	} else if game.State == types.OverState {
 		data := types.GameOverResponse{
 			ID:            game.ID,
@@ -547,6 +660,7 @@ func (h *Handler) postReady(w http.ResponseWriter, r *http.Request) {
 			Reason:        game.Reason,
 			State:         game.State,
 			LastMoveTime:  game.LastMoveTime,
+			Clock:         engine.GetClock(*game),
 		}
 
 		utils.WriteResponse(w, http.StatusOK, "Game Over", data)
@@ -564,7 +678,7 @@ func (h *Handler) postReady(w http.ResponseWriter, r *http.Request) {
 
 // auth either player
 func (h *Handler) postDraw(w http.ResponseWriter, r *http.Request) {
-	claims, statusCode, err := auth.CheckValidAuth(h.client, h.config.DB, h.config.JWT.AccessKey, r)
+	claims, statusCode, err := auth.CheckValidAuth(h.store, h.config.JWT.AccessKey, r)
 	if err != nil {
 		utils.WriteError(w, statusCode, err)
 		return
@@ -578,7 +692,7 @@ func (h *Handler) postDraw(w http.ResponseWriter, r *http.Request) {
 	}
 
 	gameID := chi.URLParam(r, "gameID")
-	game, err := db.FindGame(h.client, h.config.DB, gameID)
+	game, err := h.store.FindGame(gameID)
 	if err != nil {
 		utils.WriteError(w, http.StatusBadRequest, err)
 		return
@@ -596,20 +710,20 @@ func (h *Handler) postDraw(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err = db.GameDrawUpdate(h.client, h.config.DB, gameID, *game)
+	err = h.store.GameDrawUpdate(gameID, *game)
 	if err != nil {
 		utils.WriteError(w, http.StatusBadRequest, err)
 		return
 	}
 
 	if game.State == types.OverState {
-		gameLog, err := db.FindGameLogFromGameID(h.client, h.config.DB, gameID)
+		gameLog, err := h.store.FindGameLogFromGameID(gameID)
 		if err != nil {
 			utils.WriteError(w, http.StatusBadRequest, err)
 			return
 		}
 		engine.SetupFinalGameLog(*game, gameLog)
-		err = db.GameLogFinalUpdate(h.client, h.config.DB, gameID, *gameLog)
+		err = h.store.GameLogFinalUpdate(gameID, *gameLog)
 		if err != nil {
 			utils.WriteError(w, http.StatusBadRequest, err)
 			return
@@ -639,8 +753,37 @@ func (h *Handler) postDraw(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// auth either player
+func (h *Handler) postTakeback(w http.ResponseWriter, r *http.Request) {
+	claims, statusCode, err := auth.CheckValidAuth(h.store, h.config.JWT.AccessKey, r)
+	if err != nil {
+		utils.WriteError(w, statusCode, err)
+		return
+	}
+
+	var postTakeback types.PostTakebackRequest
+	err = utils.ParseJSON(r, &postTakeback)
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	gameID := chi.URLParam(r, "gameID")
+	_, data, err := engine.TakebackCase(gameID, claims.UserID, postTakeback, h.store)
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	if data.Count > 0 {
+		utils.WriteResponse(w, http.StatusOK, "Takeback accepted", data)
+	} else {
+		utils.WriteResponse(w, http.StatusOK, "Takeback", data)
+	}
+}
+
 func (h *Handler) getAllJoinableGames(w http.ResponseWriter, r *http.Request) {
-	games, err := db.ListAllJoinableGames(h.client, h.config.DB)
+	games, err := h.store.ListAllJoinableGames()
 	if err != nil {
 		utils.WriteError(w, http.StatusBadRequest, err)
 		return
@@ -665,7 +808,7 @@ func (h *Handler) getAllJoinableGames(w http.ResponseWriter, r *http.Request) {
 
 func (h *Handler) getPrivateGame(w http.ResponseWriter, r *http.Request) {
 	gameID := chi.URLParam(r, "gameID")
-	game, err := db.FindGame(h.client, h.config.DB, gameID)
+	game, err := h.store.FindGame(gameID)
 	if err != nil {
 		utils.WriteError(w, http.StatusBadRequest, err)
 		return
@@ -3,28 +3,33 @@ package api
 import (
 	"fmt"
 	"github.com/KainoaGardner/csc/internal/auth"
-	"github.com/KainoaGardner/csc/internal/db"
+	"github.com/KainoaGardner/csc/internal/engine"
 	"github.com/KainoaGardner/csc/internal/types"
 	"github.com/KainoaGardner/csc/internal/utils"
 	"github.com/go-chi/chi/v5"
+	"io"
 	"net/http"
+	"strconv"
 )
 
 func (h *Handler) registerGameLogRoutes(r chi.Router) {
 	r.Get("/log/all", h.getAllGameLogs)
 	r.Get("/log/{gameLogID}", h.getGameLog)
+	r.Get("/log/{gameLogID}/export", h.exportGameLog)
+	r.Get("/log/{gameLogID}/position/{ply}", h.getGameLogPosition)
+	r.Post("/log/import", h.importGameLog)
 	r.Delete("/log/all", h.deleteAllGameLogs)
 }
 
 // admin
 func (h *Handler) getAllGameLogs(w http.ResponseWriter, r *http.Request) {
-	statusCode, err := auth.CheckAdminRequest(h.client, h.config.DB, h.config.JWT.AccessKey, r)
+	statusCode, err := auth.CheckAdminRequest(h.store, h.config.JWT.AccessKey, r)
 	if err != nil {
 		utils.WriteError(w, statusCode, err)
 		return
 	}
 
-	gameLogs, err := db.ListAllGameLogs(h.client, h.config.DB)
+	gameLogs, err := h.store.ListAllGameLogs()
 	if err != nil {
 		utils.WriteError(w, http.StatusBadRequest, err)
 		return
@@ -40,13 +45,13 @@ func (h *Handler) getAllGameLogs(w http.ResponseWriter, r *http.Request) {
 
 // admin
 func (h *Handler) deleteAllGameLogs(w http.ResponseWriter, r *http.Request) {
-	statusCode, err := auth.CheckAdminRequest(h.client, h.config.DB, h.config.JWT.AccessKey, r)
+	statusCode, err := auth.CheckAdminRequest(h.store, h.config.JWT.AccessKey, r)
 	if err != nil {
 		utils.WriteError(w, statusCode, err)
 		return
 	}
 
-	amount, err := db.DeleteAllGameLogs(h.client, h.config.DB)
+	amount, err := h.store.DeleteAllGameLogs()
 	if err != nil {
 		utils.WriteError(w, http.StatusBadRequest, err)
 		return
@@ -59,7 +64,7 @@ func (h *Handler) deleteAllGameLogs(w http.ResponseWriter, r *http.Request) {
 
 func (h *Handler) getGameLog(w http.ResponseWriter, r *http.Request) {
 	gameLogID := chi.URLParam(r, "gameLogID")
-	gameLog, err := db.FindGameLog(h.client, h.config.DB, gameLogID)
+	gameLog, err := h.store.FindGameLog(gameLogID)
 	if err != nil {
 		utils.WriteError(w, http.StatusBadRequest, err)
 		return
@@ -67,3 +72,77 @@ func (h *Handler) getGameLog(w http.ResponseWriter, r *http.Request) {
 
 	utils.WriteResponse(w, http.StatusOK, "Game log", gameLog)
 }
+
+func (h *Handler) getGameLogPosition(w http.ResponseWriter, r *http.Request) {
+	gameLogID := chi.URLParam(r, "gameLogID")
+	ply, err := strconv.Atoi(chi.URLParam(r, "ply"))
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, fmt.Errorf("Invalid ply"))
+		return
+	}
+
+	gameLog, err := h.store.FindGameLog(gameLogID)
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	position, err := engine.GetReplayPosition(*gameLog, ply)
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	utils.WriteResponse(w, http.StatusOK, fmt.Sprintf("Position at ply %d", ply), position)
+}
+
+const maxImportSize = 1 << 20
+
+// player names fall back to the id when the account is gone
+func (h *Handler) getUsername(userID string) string {
+	user, err := h.store.FindUser(userID)
+	if err != nil {
+		return userID
+	}
+
+	return user.Username
+}
+
+func (h *Handler) exportGameLog(w http.ResponseWriter, r *http.Request) {
+	gameLogID := chi.URLParam(r, "gameLogID")
+	gameLog, err := h.store.FindGameLog(gameLogID)
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	record, err := engine.ExportGameLog(*gameLog, h.getUsername(gameLog.WhiteID), h.getUsername(gameLog.BlackID))
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	utils.WriteText(w, http.StatusOK, fmt.Sprintf("%s.csc", gameLogID), record)
+}
+
+// checks an exported record and returns the log it describes without saving it
+func (h *Handler) importGameLog(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxImportSize+1))
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	if len(body) > maxImportSize {
+		utils.WriteError(w, http.StatusBadRequest, fmt.Errorf("Game record limit %d bytes", maxImportSize))
+		return
+	}
+
+	gameLog, err := engine.ImportGameLog(string(body))
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	utils.WriteResponse(w, http.StatusOK, "Valid game record", gameLog)
+}
@@ -4,17 +4,17 @@ import (
 	"github.com/go-chi/chi/v5"
 
 	"github.com/KainoaGardner/csc/internal/config"
-	"go.mongodb.org/mongo-driver/mongo"
+	"github.com/KainoaGardner/csc/internal/store"
 )
 
 type Handler struct {
-	client *mongo.Client
+	store  store.Store
 	config config.Config
 }
 
-func NewHandler(client *mongo.Client, config config.Config) *Handler {
+func NewHandler(store store.Store, config config.Config) *Handler {
 	var result Handler
-	result.client = client
+	result.store = store
 	result.config = config
 
 	return &result
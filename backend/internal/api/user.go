@@ -3,7 +3,6 @@ package api
 import (
 	"fmt"
 	"github.com/KainoaGardner/csc/internal/auth"
-	"github.com/KainoaGardner/csc/internal/db"
 	"github.com/KainoaGardner/csc/internal/types"
 	"github.com/KainoaGardner/csc/internal/user"
 	"github.com/KainoaGardner/csc/internal/utils"
@@ -46,20 +45,20 @@ func (h *Handler) createUser(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err = user.CheckUniqueLogin(h.client, h.config.DB, *newUser)
+	err = user.CheckUniqueLogin(h.store, *newUser)
 	if err != nil {
 		utils.WriteError(w, http.StatusBadRequest, err)
 		return
 	}
 
-	_, err = db.CreateUser(h.client, h.config.DB, newUser)
+	_, err = h.store.CreateUser(newUser)
 	if err != nil {
 		utils.WriteError(w, http.StatusBadRequest, err)
 		return
 	}
 
 	userStats := user.SetupUserStats(*newUser)
-	_, err = db.CreateUserStats(h.client, h.config.DB, userStats)
+	_, err = h.store.CreateUserStats(userStats)
 
 	data := types.UserResponse{
 		ID:          newUser.ID,
@@ -74,7 +73,7 @@ func (h *Handler) createUser(w http.ResponseWriter, r *http.Request) {
 
 // admin
 func (h *Handler) getUser(w http.ResponseWriter, r *http.Request) {
-	statusCode, err := auth.CheckAdminRequest(h.client, h.config.DB, h.config.JWT.AccessKey, r)
+	statusCode, err := auth.CheckAdminRequest(h.store, h.config.JWT.AccessKey, r)
 	if err != nil {
 		utils.WriteError(w, statusCode, err)
 		return
@@ -82,7 +81,7 @@ func (h *Handler) getUser(w http.ResponseWriter, r *http.Request) {
 
 	userID := chi.URLParam(r, "userID")
 
-	dbUser, err := db.FindUser(h.client, h.config.DB, userID)
+	dbUser, err := h.store.FindUser(userID)
 	if err != nil {
 		utils.WriteError(w, http.StatusBadRequest, err)
 		return
@@ -93,13 +92,13 @@ func (h *Handler) getUser(w http.ResponseWriter, r *http.Request) {
 
 // auth
 func (h *Handler) getAuthUser(w http.ResponseWriter, r *http.Request) {
-	claims, statusCode, err := auth.CheckValidAuth(h.client, h.config.DB, h.config.JWT.AccessKey, r)
+	claims, statusCode, err := auth.CheckValidAuth(h.store, h.config.JWT.AccessKey, r)
 	if err != nil {
 		utils.WriteError(w, statusCode, err)
 		return
 	}
 
-	dbUser, err := db.FindUser(h.client, h.config.DB, claims.UserID)
+	dbUser, err := h.store.FindUser(claims.UserID)
 	if err != nil {
 		utils.WriteError(w, http.StatusBadRequest, err)
 		return
@@ -117,13 +116,13 @@ func (h *Handler) getAuthUser(w http.ResponseWriter, r *http.Request) {
 
 // auth
 func (h *Handler) deleteUser(w http.ResponseWriter, r *http.Request) {
-	claims, statusCode, err := auth.CheckValidAuth(h.client, h.config.DB, h.config.JWT.AccessKey, r)
+	claims, statusCode, err := auth.CheckValidAuth(h.store, h.config.JWT.AccessKey, r)
 	if err != nil {
 		utils.WriteError(w, statusCode, err)
 		return
 	}
 
-	count, err := db.DeleteUser(h.client, h.config.DB, claims.UserID)
+	count, err := h.store.DeleteUser(claims.UserID)
 	if err != nil {
 		utils.WriteError(w, http.StatusBadRequest, err)
 		return
@@ -134,13 +133,13 @@ func (h *Handler) deleteUser(w http.ResponseWriter, r *http.Request) {
 
 // admin
 func (h *Handler) getAllUsers(w http.ResponseWriter, r *http.Request) {
-	statusCode, err := auth.CheckAdminRequest(h.client, h.config.DB, h.config.JWT.AccessKey, r)
+	statusCode, err := auth.CheckAdminRequest(h.store, h.config.JWT.AccessKey, r)
 	if err != nil {
 		utils.WriteError(w, statusCode, err)
 		return
 	}
 
-	users, err := db.ListAllUsers(h.client, h.config.DB)
+	users, err := h.store.ListAllUsers()
 	if err != nil {
 		utils.WriteError(w, http.StatusBadRequest, err)
 		return
@@ -163,13 +162,13 @@ func (h *Handler) getAllUsers(w http.ResponseWriter, r *http.Request) {
 
 // admin
 func (h *Handler) deleteAllUsers(w http.ResponseWriter, r *http.Request) {
-	statusCode, err := auth.CheckAdminRequest(h.client, h.config.DB, h.config.JWT.AccessKey, r)
+	statusCode, err := auth.CheckAdminRequest(h.store, h.config.JWT.AccessKey, r)
 	if err != nil {
 		utils.WriteError(w, statusCode, err)
 		return
 	}
 
-	amount, err := db.DeleteAllUsers(h.client, h.config.DB)
+	amount, err := h.store.DeleteAllUsers()
 	if err != nil {
 		utils.WriteError(w, http.StatusBadRequest, err)
 		return
@@ -189,7 +188,7 @@ func (h *Handler) loginUser(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	dbUser, err := db.FindUserFromUsername(h.client, h.config.DB, postLogin.Username)
+	dbUser, err := h.store.FindUserFromUsername(postLogin.Username)
 	if err != nil {
 		utils.WriteError(w, http.StatusBadRequest, err)
 		return
@@ -306,7 +305,7 @@ func (h *Handler) forgotPassword(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	dbUser, err := db.FindUserFromEmail(h.client, h.config.DB, postForgot.Email)
+	dbUser, err := h.store.FindUserFromEmail(postForgot.Email)
 	if err != nil {
 		utils.WriteError(w, http.StatusBadRequest, err)
 		return
@@ -331,7 +330,7 @@ func (h *Handler) forgotPassword(w http.ResponseWriter, r *http.Request) {
 }
 
 func (h *Handler) resetPassword(w http.ResponseWriter, r *http.Request) {
-	claims, statusCode, err := auth.CheckValidAuth(h.client, h.config.DB, h.config.JWT.PasswordRefreshKey, r)
+	claims, statusCode, err := auth.CheckValidAuth(h.store, h.config.JWT.PasswordRefreshKey, r)
 	if err != nil {
 		utils.WriteError(w, statusCode, err)
 		return
@@ -350,7 +349,7 @@ func (h *Handler) resetPassword(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	_, err = db.UpdateUserPassword(h.client, h.config.DB, claims.ID, passwordHash)
+	_, err = h.store.UpdateUserPassword(claims.ID, passwordHash)
 	if err != nil {
 		utils.WriteError(w, http.StatusBadRequest, err)
 		return
@@ -3,11 +3,12 @@ package api
 import (
 	"fmt"
 	"github.com/KainoaGardner/csc/internal/auth"
-	"github.com/KainoaGardner/csc/internal/db"
+	"github.com/KainoaGardner/csc/internal/rating"
 	"github.com/KainoaGardner/csc/internal/types"
 	"github.com/KainoaGardner/csc/internal/utils"
 	"github.com/go-chi/chi/v5"
 	"net/http"
+	"strconv"
 )
 
 func (h *Handler) registerUserStatRoutes(r chi.Router) {
@@ -16,57 +17,73 @@ func (h *Handler) registerUserStatRoutes(r chi.Router) {
 	r.Get("/userStat/all", h.getAllUserStats)
 	r.Get("/userStat/gameLogs", h.getAuthUserGameLogs)
 	r.Get("/userStat/{userID}/gameLogs", h.getUserGameLogs)
+	r.Get("/userStat/{userID}/breakdown", h.getUserBreakdown)
+	r.Get("/userStat/{userID}/vs/{opponentID}", h.getHeadToHead)
+	r.Get("/leaderboard", h.getLeaderboard)
+}
+
+var leaderboardSorts = map[string]bool{
+	"rating":      true,
+	"wins":        true,
+	"winRate":     true,
+	"gamesPlayed": true,
+}
+
+func getQueryInt(r *http.Request, key string, defaultValue int, maxValue int) (int, error) {
+	str := r.URL.Query().Get(key)
+	if str == "" {
+		return defaultValue, nil
+	}
+
+	value, err := strconv.Atoi(str)
+	if err != nil || value < 1 || value > maxValue {
+		return 0, fmt.Errorf("%s must be between 1 and %d", key, maxValue)
+	}
+
+	return value, nil
 }
 
 // auth
 func (h *Handler) getAuthUserStats(w http.ResponseWriter, r *http.Request) {
-	claims, statusCode, err := auth.CheckValidAuth(h.client, h.config.DB, h.config.JWT.AccessKey, r)
+	claims, statusCode, err := auth.CheckValidAuth(h.store, h.config.JWT.AccessKey, r)
 	if err != nil {
 		utils.WriteError(w, statusCode, err)
 		return
 	}
 
-	userStats, err := db.FindUserStatsFromUserID(h.client, h.config.DB, claims.UserID)
+	userStats, err := h.store.FindUserStatsFromUserID(claims.UserID)
 	if err != nil {
 		utils.WriteError(w, http.StatusBadRequest, err)
 		return
 	}
 
-	data := types.UserStatsResponse{
-		GamesPlayed: userStats.GamesPlayed,
-		GamesWon:    userStats.GamesWon,
-		GameLog:     userStats.GameLogs,
-	}
+	data := setupUserStatsResponse(*userStats)
 
 	utils.WriteResponse(w, http.StatusOK, "User Stats", data)
 }
 
 func (h *Handler) getUserStats(w http.ResponseWriter, r *http.Request) {
 	userID := chi.URLParam(r, "userID")
-	userStats, err := db.FindUserStatsFromUserID(h.client, h.config.DB, userID)
+	userStats, err := h.store.FindUserStatsFromUserID(userID)
 	if err != nil {
 		utils.WriteError(w, http.StatusBadRequest, err)
 		return
 	}
 
-	data := types.UserStatsResponse{
-		GamesPlayed: userStats.GamesPlayed,
-		GamesWon:    userStats.GamesWon,
-		GameLog:     userStats.GameLogs,
-	}
+	data := setupUserStatsResponse(*userStats)
 
 	utils.WriteResponse(w, http.StatusOK, "User Stats", data)
 }
 
 // admin
 func (h *Handler) getAllUserStats(w http.ResponseWriter, r *http.Request) {
-	statusCode, err := auth.CheckAdminRequest(h.client, h.config.DB, h.config.JWT.AccessKey, r)
+	statusCode, err := auth.CheckAdminRequest(h.store, h.config.JWT.AccessKey, r)
 	if err != nil {
 		utils.WriteError(w, statusCode, err)
 		return
 	}
 
-	userStats, err := db.ListAllUserStats(h.client, h.config.DB)
+	userStats, err := h.store.ListAllUserStats()
 	if err != nil {
 		utils.WriteError(w, http.StatusBadRequest, err)
 		return
@@ -82,7 +99,7 @@ func (h *Handler) getAllUserStats(w http.ResponseWriter, r *http.Request) {
 
 func (h *Handler) getUserGameLogs(w http.ResponseWriter, r *http.Request) {
 	userID := chi.URLParam(r, "userID")
-	userStats, err := db.FindUserStatsFromUserID(h.client, h.config.DB, userID)
+	userStats, err := h.store.FindUserStatsFromUserID(userID)
 	if err != nil {
 		utils.WriteError(w, http.StatusBadRequest, err)
 		return
@@ -93,7 +110,7 @@ func (h *Handler) getUserGameLogs(w http.ResponseWriter, r *http.Request) {
 	minGameAmount := min(len(userStats.GameLogs), 5)
 	for i := 0; i < minGameAmount; i++ {
 		gameLogID := userStats.GameLogs[len(userStats.GameLogs)-1-i]
-		gameLog, err := db.FindGameLog(h.client, h.config.DB, gameLogID)
+		gameLog, err := h.store.FindGameLog(gameLogID)
 		if err != nil {
 			utils.WriteError(w, http.StatusBadRequest, err)
 			return
@@ -116,13 +133,13 @@ func (h *Handler) getUserGameLogs(w http.ResponseWriter, r *http.Request) {
 
 // auth
 func (h *Handler) getAuthUserGameLogs(w http.ResponseWriter, r *http.Request) {
-	claims, statusCode, err := auth.CheckValidAuth(h.client, h.config.DB, h.config.JWT.AccessKey, r)
+	claims, statusCode, err := auth.CheckValidAuth(h.store, h.config.JWT.AccessKey, r)
 	if err != nil {
 		utils.WriteError(w, statusCode, err)
 		return
 	}
 
-	userStats, err := db.FindUserStatsFromUserID(h.client, h.config.DB, claims.UserID)
+	userStats, err := h.store.FindUserStatsFromUserID(claims.UserID)
 	if err != nil {
 		utils.WriteError(w, http.StatusBadRequest, err)
 		return
@@ -134,7 +151,7 @@ func (h *Handler) getAuthUserGameLogs(w http.ResponseWriter, r *http.Request) {
 	minGameAmount := min(len(userStats.GameLogs), 5)
 	for i := 0; i < minGameAmount; i++ {
 		gameLogID := userStats.GameLogs[len(userStats.GameLogs)-1-i]
-		gameLog, err := db.FindGameLog(h.client, h.config.DB, gameLogID)
+		gameLog, err := h.store.FindGameLog(gameLogID)
 
 		if err != nil {
 			continue
@@ -156,3 +173,89 @@ func (h *Handler) getAuthUserGameLogs(w http.ResponseWriter, r *http.Request) {
 
 	utils.WriteResponse(w, http.StatusOK, "Last 5 Game Logs", data)
 }
+
+func setupUserStatsResponse(userStats types.UserStats) types.UserStatsResponse {
+	userRating := rating.SetupRating(userStats.Rating, userStats.RD, userStats.Volatility)
+
+	result := types.UserStatsResponse{
+		GamesPlayed:   userStats.GamesPlayed,
+		GamesWon:      userStats.GamesWon,
+		GameLog:       userStats.GameLogs,
+		Rating:        userRating.Rating,
+		RD:            userRating.RD,
+		RatingHistory: userStats.RatingHistory,
+	}
+	if result.RatingHistory == nil {
+		result.RatingHistory = []types.RatingRecord{}
+	}
+
+	return result
+}
+
+func (h *Handler) getLeaderboard(w http.ResponseWriter, r *http.Request) {
+	sort := r.URL.Query().Get("sort")
+	if sort == "" {
+		sort = "rating"
+	}
+	if !leaderboardSorts[sort] {
+		utils.WriteError(w, http.StatusBadRequest, fmt.Errorf("Invalid sort %s", sort))
+		return
+	}
+
+	page, err := getQueryInt(r, "page", 1, 100000)
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	limit, err := getQueryInt(r, "limit", 20, 100)
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	entries, err := h.store.FindLeaderboard(sort, page, limit)
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	data := types.LeaderboardResponse{
+		Sort:    sort,
+		Page:    page,
+		Limit:   limit,
+		Entries: entries,
+	}
+
+	utils.WriteResponse(w, http.StatusOK, "Leaderboard", data)
+}
+
+func (h *Handler) getUserBreakdown(w http.ResponseWriter, r *http.Request) {
+	userID := chi.URLParam(r, "userID")
+
+	data, err := h.store.FindUserBreakdown(userID)
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	utils.WriteResponse(w, http.StatusOK, "User Breakdown", data)
+}
+
+func (h *Handler) getHeadToHead(w http.ResponseWriter, r *http.Request) {
+	userID := chi.URLParam(r, "userID")
+	opponentID := chi.URLParam(r, "opponentID")
+
+	if userID == opponentID {
+		utils.WriteError(w, http.StatusBadRequest, fmt.Errorf("Cannot compare user with themselves"))
+		return
+	}
+
+	data, err := h.store.FindHeadToHead(userID, opponentID)
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	utils.WriteResponse(w, http.StatusOK, "Head To Head", data)
+}
@@ -3,6 +3,9 @@ package api
 import (
 	"fmt"
 	"github.com/KainoaGardner/csc/internal/auth"
+	"github.com/KainoaGardner/csc/internal/engine"
+	"github.com/KainoaGardner/csc/internal/matchmaking"
+	"github.com/KainoaGardner/csc/internal/types"
 	"github.com/KainoaGardner/csc/internal/utils"
 	"github.com/KainoaGardner/csc/internal/websockets"
 	"github.com/go-chi/chi/v5"
@@ -18,6 +21,9 @@ var upgrader = websocket.Upgrader{
 
 func (h *Handler) registerWebsocketRoutes(r chi.Router) {
 	r.Get("/ws/{gameID}/{accessToken}", h.connectToGame)
+	r.Get("/ws/{gameID}", h.spectateGame)
+	r.Get("/ws/lobby/{accessToken}", h.connectToLobby)
+	r.Get("/ws/replay/{gameLogID}", h.replayGameLog)
 }
 
 // auth
@@ -47,7 +53,124 @@ func (h *Handler) connectToGame(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	websockets.AddPlayerToGame(gameID, claims.UserID, conn)
+	reconnected := websockets.AddPlayerToGame(gameID, claims.UserID, conn)
+	if reconnected {
+		websockets.ReconnectPlayer(gameID, claims.UserID, h.store, h.config)
+	}
+
+	go websockets.HandleMessages(gameID, claims.UserID, conn, h.store, h.config)
+}
+
+// auth
+func (h *Handler) connectToLobby(w http.ResponseWriter, r *http.Request) {
+	accessToken := chi.URLParam(r, "accessToken")
+
+	if accessToken == "" {
+		utils.WriteError(w, http.StatusBadRequest, fmt.Errorf("Missing accessToken"))
+		return
+	}
+
+	claims, err := auth.ParseToken(h.config.JWT.AccessKey, accessToken)
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	if auth.CheckExpiredToken(claims) {
+		utils.WriteError(w, http.StatusUnauthorized, fmt.Errorf("Token expired"))
+		return
+	}
+
+	conn, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	matchmaking.AddPlayerToLobby(claims.UserID, conn)
+
+	go matchmaking.HandleLobbyMessages(claims.UserID, conn)
+}
+
+func (h *Handler) spectateGame(w http.ResponseWriter, r *http.Request) {
+	gameID := chi.URLParam(r, "gameID")
+
+	game, err := h.store.FindGame(gameID)
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	err = engine.CheckCanSpectate(*game, h.getSpectatorID(r))
+	if err != nil {
+		utils.WriteError(w, http.StatusUnauthorized, err)
+		return
+	}
+
+	fen, err := engine.ConvertBoardToString(*game)
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	conn, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	data := types.SpectateResponse{
+		ID:      game.ID,
+		WhiteID: game.WhiteID,
+		BlackID: game.BlackID,
+		State:   game.State,
+		FEN:     fen,
+		Clock:   engine.GetClock(*game),
+	}
+	websockets.AddSpectatorToGame(gameID, conn, data)
+
+	go websockets.HandleSpectatorMessages(gameID, conn)
+}
+
+// browsers cannot set headers on websockets so the token can also come in the query
+func (h *Handler) getSpectatorID(r *http.Request) string {
+	accessToken := r.URL.Query().Get("accessToken")
+	if accessToken == "" {
+		token, err := auth.GetTokenFromRequest(r)
+		if err != nil {
+			return ""
+		}
+		accessToken = token
+	}
+
+	claims, err := auth.ParseToken(h.config.JWT.AccessKey, accessToken)
+	if err != nil || auth.CheckExpiredToken(claims) {
+		return ""
+	}
+
+	return claims.UserID
+}
+
+func (h *Handler) replayGameLog(w http.ResponseWriter, r *http.Request) {
+	gameLogID := chi.URLParam(r, "gameLogID")
+
+	gameLog, err := h.store.FindGameLog(gameLogID)
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	_, err = engine.GetReplayPosition(*gameLog, 0)
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	conn, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, err)
+		return
+	}
 
-	go websockets.HandleMessages(gameID, claims.UserID, conn, h.client, h.config)
+	go websockets.HandleReplay(*gameLog, conn)
 }
@@ -2,11 +2,9 @@ package auth
 
 import (
 	"fmt"
-	"github.com/KainoaGardner/csc/internal/config"
-	"github.com/KainoaGardner/csc/internal/db"
+	"github.com/KainoaGardner/csc/internal/store"
 	"github.com/KainoaGardner/csc/internal/types"
 	"github.com/golang-jwt/jwt/v5"
-	"go.mongodb.org/mongo-driver/mongo"
 	"golang.org/x/crypto/bcrypt"
 	"net/http"
 	"strings"
@@ -96,13 +94,13 @@ func CheckExpiredToken(claims *types.TokenClaims) bool {
 	return false
 }
 
-func CheckAdminRequest(client *mongo.Client, dbConfig config.DB, jwtKey string, r *http.Request) (int, error) {
-	claims, statusCode, err := CheckValidAuth(client, dbConfig, jwtKey, r)
+func CheckAdminRequest(userStore store.UserStore, jwtKey string, r *http.Request) (int, error) {
+	claims, statusCode, err := CheckValidAuth(userStore, jwtKey, r)
 	if err != nil {
 		return statusCode, err
 	}
 
-	dbUser, err := db.FindUser(client, dbConfig, claims.UserID)
+	dbUser, err := userStore.FindUser(claims.UserID)
 	if err != nil {
 		return http.StatusBadRequest, err
 	}
@@ -114,7 +112,7 @@ func CheckAdminRequest(client *mongo.Client, dbConfig config.DB, jwtKey string,
 	return http.StatusOK, nil
 }
 
-func CheckValidAuth(client *mongo.Client, dbConfig config.DB, jwtKey string, r *http.Request) (*types.TokenClaims, int, error) {
+func CheckValidAuth(userStore store.UserStore, jwtKey string, r *http.Request) (*types.TokenClaims, int, error) {
 	claims, err := GetTokenClaims(jwtKey, r)
 	if err != nil {
 		return nil, http.StatusBadRequest, err
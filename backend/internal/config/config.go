@@ -4,16 +4,25 @@ import (
 	"github.com/joho/godotenv"
 	"log"
 	"os"
+	"strconv"
+	"time"
 )
 
 type Config struct {
-	DB         DB
-	PublicHost string
-	Port       string
-	JWT        JWT
-	Email      EMAIL
+	DB              DB
+	PublicHost      string
+	Port            string
+	JWT             JWT
+	Email           EMAIL
+	DisconnectGrace time.Duration
+	Storage         string
 }
 
+const (
+	MongoStorage  = "mongo"
+	MemoryStorage = "memory" //nothing is saved once the server stops
+)
+
 type EMAIL struct {
 	From     string
 	Password string
@@ -38,13 +47,6 @@ type Collections struct {
 	GameLogs  string
 }
 
-func init() {
-	err := godotenv.Load()
-	if err != nil {
-		log.Fatal("Error loading .env")
-	}
-}
-
 func checkGetenv(key string) string {
 	val := os.Getenv(key)
 	if val == "" {
@@ -53,9 +55,27 @@ func checkGetenv(key string) string {
 	return val
 }
 
+func getenvSeconds(key string, defaultSeconds int) time.Duration {
+	val := os.Getenv(key)
+	if val == "" {
+		return time.Duration(defaultSeconds) * time.Second
+	}
+
+	seconds, err := strconv.Atoi(val)
+	if err != nil || seconds < 0 {
+		log.Fatal("Env variable must be a non negative number of seconds: ", key)
+	}
+	return time.Duration(seconds) * time.Second
+}
+
 func LoadConfig() Config {
 	var result Config
 
+	err := godotenv.Load()
+	if err != nil {
+		log.Fatal("Error loading .env")
+	}
+
 	result.PublicHost = checkGetenv("PUBLIC_HOST")
 	result.Port = checkGetenv("PORT")
 
@@ -63,16 +83,30 @@ func LoadConfig() Config {
 	result.JWT.RefreshKey = checkGetenv("JWT_REFRESH_KEY")
 	result.JWT.PasswordRefreshKey = checkGetenv("JWT_PASSWORD_REFRESH_KEY")
 
-	result.DB.Name = os.Getenv("MONGODB_DB")
-	result.DB.Uri = checkGetenv("MONGODB_URI")
+	result.Storage = os.Getenv("STORAGE")
+	switch result.Storage {
+	case "":
+		result.Storage = MongoStorage
+	case MongoStorage, MemoryStorage:
+	default:
+		log.Fatal("Env variable STORAGE must be mongo or memory: ", result.Storage)
+	}
+
+	//mongo settings are only needed when games are stored in mongo
+	if result.Storage == MongoStorage {
+		result.DB.Name = os.Getenv("MONGODB_DB")
+		result.DB.Uri = checkGetenv("MONGODB_URI")
 
-	result.DB.Collections.Users = checkGetenv("MONGODB_USERS_COLLECTION")
-	result.DB.Collections.UserStats = checkGetenv("MONGODB_USER_STATS_COLLECTION")
-	result.DB.Collections.Games = checkGetenv("MONGODB_GAMES_COLLECTION")
-	result.DB.Collections.GameLogs = checkGetenv("MONGODB_GAME_LOGS_COLLECTION")
+		result.DB.Collections.Users = checkGetenv("MONGODB_USERS_COLLECTION")
+		result.DB.Collections.UserStats = checkGetenv("MONGODB_USER_STATS_COLLECTION")
+		result.DB.Collections.Games = checkGetenv("MONGODB_GAMES_COLLECTION")
+		result.DB.Collections.GameLogs = checkGetenv("MONGODB_GAME_LOGS_COLLECTION")
+	}
 
 	result.Email.Password = checkGetenv("EMAIL_APP_PASSWORD")
 	result.Email.From = checkGetenv("EMAIL_FROM")
 
+	result.DisconnectGrace = getenvSeconds("DISCONNECT_GRACE_SECONDS", 30)
+
 	return result
 }
@@ -109,7 +109,32 @@ func GameLogUpdate(client *mongo.Client, db config.DB, gameID string, moveString
 	return nil
 }
 
+func GameLogTakebackUpdate(client *mongo.Client, db config.DB, gameID string, count int) error {
+	gameLog, err := FindGameLogFromGameID(client, db, gameID)
+	if err != nil {
+		return err
+	}
+
+	moves := gameLog.Moves[:max(len(gameLog.Moves)-count, 0)]
+	boardStates := gameLog.BoardStates[:max(len(gameLog.BoardStates)-count, 0)]
+
+	filter := bson.M{"_id": gameLog.ID}
+	update := bson.M{"$set": bson.M{"moves": moves, "boardStates": boardStates}}
+
+	collection := client.Database(db.Name).Collection(db.Collections.GameLogs)
+	_, err = collection.UpdateOne(context.Background(), filter, update)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
 func GameLogFinalUpdate(client *mongo.Client, db config.DB, gameID string, gameLog types.GameLog) error {
+	return gameLogFinalUpdate(context.Background(), client, db, gameID, gameLog)
+}
+
+func gameLogFinalUpdate(ctx context.Context, client *mongo.Client, db config.DB, gameID string, gameLog types.GameLog) error {
 	id, err := primitive.ObjectIDFromHex(gameID)
 	if err != nil {
 		return err
@@ -119,7 +144,7 @@ func GameLogFinalUpdate(client *mongo.Client, db config.DB, gameID string, gameL
 	update := bson.M{"$set": gameLog}
 
 	collection := client.Database(db.Name).Collection(db.Collections.GameLogs)
-	_, err = collection.UpdateOne(context.Background(), filter, update)
+	_, err = collection.UpdateOne(ctx, filter, update)
 	if err != nil {
 		return err
 	}
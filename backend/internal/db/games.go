@@ -2,6 +2,7 @@ package db
 
 import (
 	"context"
+	"fmt"
 	"github.com/KainoaGardner/csc/internal/config"
 	"github.com/KainoaGardner/csc/internal/types"
 	"go.mongodb.org/mongo-driver/bson"
@@ -38,6 +39,24 @@ func ListAllGames(client *mongo.Client, db config.DB) ([]*types.Game, error) {
 	return games, nil
 }
 
+func ListGamesInState(client *mongo.Client, db config.DB, state int) ([]*types.Game, error) {
+	var games []*types.Game
+
+	collection := client.Database(db.Name).Collection(db.Collections.Games)
+
+	cursor, err := collection.Find(context.Background(), bson.M{"state": state})
+	if err != nil {
+		return nil, err
+	}
+
+	err = cursor.All(context.Background(), &games)
+	if err != nil {
+		return nil, err
+	}
+
+	return games, nil
+}
+
 func DeleteAllGames(client *mongo.Client, db config.DB) (int, error) {
 	collection := client.Database(db.Name).Collection(db.Collections.Games)
 	result, err := collection.DeleteMany(context.Background(), bson.M{}, nil)
@@ -49,6 +68,10 @@ func DeleteAllGames(client *mongo.Client, db config.DB) (int, error) {
 }
 
 func DeleteGame(client *mongo.Client, db config.DB, gameID string) (int, error) {
+	return deleteGame(context.Background(), client, db, gameID)
+}
+
+func deleteGame(ctx context.Context, client *mongo.Client, db config.DB, gameID string) (int, error) {
 	id, err := primitive.ObjectIDFromHex(gameID)
 	if err != nil {
 		return 0, err
@@ -56,7 +79,7 @@ func DeleteGame(client *mongo.Client, db config.DB, gameID string) (int, error)
 
 	filter := bson.M{"_id": id}
 	collection := client.Database(db.Name).Collection(db.Collections.Games)
-	result, err := collection.DeleteOne(context.Background(), filter)
+	result, err := collection.DeleteOne(ctx, filter)
 	if err != nil {
 		return 0, err
 	}
@@ -64,6 +87,38 @@ func DeleteGame(client *mongo.Client, db config.DB, gameID string) (int, error)
 	return int(result.DeletedCount), nil
 }
 
+// final log, both players stats and the game delete are written together or not at all
+func FinishGame(client *mongo.Client, db config.DB, gameID string, gameLog types.GameLog, statsUpdates map[string]types.UpdateUserStats) error {
+	session, err := client.StartSession()
+	if err != nil {
+		return err
+	}
+	defer session.EndSession(context.Background())
+
+	_, err = session.WithTransaction(context.Background(), func(ctx mongo.SessionContext) (interface{}, error) {
+		err := gameLogFinalUpdate(ctx, client, db, gameID, gameLog)
+		if err != nil {
+			return nil, err
+		}
+
+		for userID, statsUpdate := range statsUpdates {
+			err = updateUserStats(ctx, client, db, userID, statsUpdate)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		_, err = deleteGame(ctx, client, db, gameID)
+		if err != nil {
+			return nil, err
+		}
+
+		return nil, nil
+	})
+
+	return err
+}
+
 func FindGame(client *mongo.Client, db config.DB, gameID string) (*types.Game, error) {
 	var result types.Game
 
@@ -83,94 +138,70 @@ func FindGame(client *mongo.Client, db config.DB, gameID string) (*types.Game, e
 	return &result, nil
 }
 
-func GamePlaceUpdate(client *mongo.Client, db config.DB, gameID string, place types.Place, game types.Game) error {
-	id, err := primitive.ObjectIDFromHex(gameID)
-	if err != nil {
-		return err
-	}
+var ErrVersionConflict = fmt.Errorf("Game was updated by another request")
 
-	filter := bson.M{"_id": id}
-	update := bson.M{"$set": bson.M{"board.board": game.Board.Board, "money": game.Money}}
-
-	collection := client.Database(db.Name).Collection(db.Collections.Games)
-	_, err = collection.UpdateOne(context.Background(), filter, update)
-	if err != nil {
-		return err
+func getVersionFilter(id primitive.ObjectID, version int64) bson.M {
+	if version == 0 {
+		//games from before versioning have no version field
+		return bson.M{"_id": id, "version": bson.M{"$in": bson.A{0, nil}}}
 	}
 
-	return nil
+	return bson.M{"_id": id, "version": version}
 }
 
-func GameMoveUpdate(client *mongo.Client, db config.DB, gameID string, game types.Game) error {
+// only writes if nobody else updated the game since it was loaded
+func updateGameVersion(client *mongo.Client, db config.DB, gameID string, version int64, update bson.M) error {
 	id, err := primitive.ObjectIDFromHex(gameID)
 	if err != nil {
 		return err
 	}
 
-	filter := bson.M{"_id": id}
-	update := bson.M{"$set": game}
+	filter := getVersionFilter(id, version)
 
 	collection := client.Database(db.Name).Collection(db.Collections.Games)
-	_, err = collection.UpdateOne(context.Background(), filter, update)
+	result, err := collection.UpdateOne(context.Background(), filter, update)
 	if err != nil {
 		return err
 	}
 
+	if result.MatchedCount == 0 {
+		return ErrVersionConflict
+	}
+
 	return nil
 }
 
-func GameStateUpdate(client *mongo.Client, db config.DB, gameID string, game types.Game) error {
-	id, err := primitive.ObjectIDFromHex(gameID)
-	if err != nil {
-		return err
-	}
+func GamePlaceUpdate(client *mongo.Client, db config.DB, gameID string, game types.Game) error {
+	update := bson.M{"$set": bson.M{"board.board": game.Board.Board, "money": game.Money, "placements": game.Placements, "version": game.Version + 1}}
+	return updateGameVersion(client, db, gameID, game.Version, update)
+}
 
-	filter := bson.M{"_id": id}
-	update := bson.M{"$set": bson.M{"state": game.State}}
+func GameMoveUpdate(client *mongo.Client, db config.DB, gameID string, game types.Game) error {
+	version := game.Version
+	game.Version++
 
-	collection := client.Database(db.Name).Collection(db.Collections.Games)
-	_, err = collection.UpdateOne(context.Background(), filter, update)
-	if err != nil {
-		return err
-	}
+	update := bson.M{"$set": game}
+	return updateGameVersion(client, db, gameID, version, update)
+}
 
-	return nil
+func GameStateUpdate(client *mongo.Client, db config.DB, gameID string, game types.Game) error {
+	update := bson.M{"$set": bson.M{"state": game.State, "version": game.Version + 1}}
+	return updateGameVersion(client, db, gameID, game.Version, update)
 }
 
 func GameReadyUpdate(client *mongo.Client, db config.DB, gameID string, game types.Game) error {
-	id, err := primitive.ObjectIDFromHex(gameID)
-	if err != nil {
-		return err
-	}
-
-	filter := bson.M{"_id": id}
-	update := bson.M{"$set": bson.M{"state": game.State, "ready": game.Ready, "lastMoveTime": game.LastMoveTime}}
-
-	collection := client.Database(db.Name).Collection(db.Collections.Games)
-	_, err = collection.UpdateOne(context.Background(), filter, update)
-	if err != nil {
-		return err
-	}
-
-	return nil
+	update := bson.M{"$set": bson.M{"state": game.State, "ready": game.Ready, "lastMoveTime": game.LastMoveTime, "version": game.Version + 1}}
+	return updateGameVersion(client, db, gameID, game.Version, update)
 }
 
 func GameDrawUpdate(client *mongo.Client, db config.DB, gameID string, game types.Game) error {
-	id, err := primitive.ObjectIDFromHex(gameID)
-	if err != nil {
-		return err
-	}
-
-	filter := bson.M{"_id": id}
-	update := bson.M{"$set": bson.M{"draw": game.Draw}}
-
-	collection := client.Database(db.Name).Collection(db.Collections.Games)
-	_, err = collection.UpdateOne(context.Background(), filter, update)
-	if err != nil {
-		return err
-	}
+	update := bson.M{"$set": bson.M{"draw": game.Draw, "version": game.Version + 1}}
+	return updateGameVersion(client, db, gameID, game.Version, update)
+}
 
-	return nil
+func GameTakebackUpdate(client *mongo.Client, db config.DB, gameID string, game types.Game) error {
+	update := bson.M{"$set": bson.M{"takeback": game.Takeback, "version": game.Version + 1}}
+	return updateGameVersion(client, db, gameID, game.Version, update)
 }
 
 func ListAllJoinableGames(client *mongo.Client, db config.DB) ([]types.Game, error) {
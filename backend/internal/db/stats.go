@@ -0,0 +1,244 @@
+package db
+
+import (
+	"context"
+	"github.com/KainoaGardner/csc/internal/config"
+	"github.com/KainoaGardner/csc/internal/rating"
+	"github.com/KainoaGardner/csc/internal/types"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+const (
+	winResult  = "win"
+	lossResult = "loss"
+	drawResult = "draw"
+)
+
+// games against the computer are unrated so they stay out of the stats too
+func getFinishedGameFilter() bson.M {
+	return bson.M{
+		"winner":  bson.M{"$ne": nil},
+		"whiteID": bson.M{"$ne": types.BotID},
+		"blackID": bson.M{"$ne": types.BotID},
+	}
+}
+
+type resultGroup struct {
+	ID struct {
+		Color  int    `bson:"color"`
+		Reason string `bson:"reason"`
+		Result string `bson:"result"`
+	} `bson:"_id"`
+	Count int `bson:"count"`
+}
+
+type resultSummary struct {
+	GamesPlayed      int     `bson:"gamesPlayed"`
+	AverageMoveCount float64 `bson:"averageMoveCount"`
+}
+
+// color and result of each game from the given user's side
+func getUserResultStages(userID string) mongo.Pipeline {
+	color := bson.M{"$cond": bson.A{bson.M{"$eq": bson.A{"$whiteID", userID}}, types.White, types.Black}}
+
+	return mongo.Pipeline{
+		{{Key: "$addFields", Value: bson.M{"color": color}}},
+		{{Key: "$addFields", Value: bson.M{"result": getResultExpression("$color")}}},
+	}
+}
+
+// color is a side or a field path holding one
+func getResultExpression(color interface{}) bson.M {
+	return bson.M{"$switch": bson.M{
+		"branches": bson.A{
+			bson.M{"case": bson.M{"$eq": bson.A{"$winner", types.Tie}}, "then": drawResult},
+			bson.M{"case": bson.M{"$eq": bson.A{"$winner", color}}, "then": winResult},
+		},
+		"default": lossResult,
+	}}
+}
+
+func addResult(counts *types.ResultCounts, result string, count int) {
+	switch result {
+	case winResult:
+		counts.Wins += count
+	case lossResult:
+		counts.Losses += count
+	case drawResult:
+		counts.Draws += count
+	}
+}
+
+func FindLeaderboard(client *mongo.Client, db config.DB, sort string, page int, limit int) ([]types.LeaderboardEntry, error) {
+	entries := []types.LeaderboardEntry{}
+
+	players := bson.A{
+		bson.M{"userID": "$whiteID", "result": getResultExpression(types.White)},
+		bson.M{"userID": "$blackID", "result": getResultExpression(types.Black)},
+	}
+	isResult := func(result string) bson.M {
+		return bson.M{"$sum": bson.M{"$cond": bson.A{bson.M{"$eq": bson.A{"$result", result}}, 1, 0}}}
+	}
+	statsRating := bson.M{"$let": bson.M{
+		"vars": bson.M{"rating": bson.M{"$first": "$stats.rating"}},
+		"in":   bson.M{"$cond": bson.A{bson.M{"$gt": bson.A{"$$rating", 0}}, "$$rating", rating.DefaultRating}},
+	}}
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: getFinishedGameFilter()}},
+		{{Key: "$project", Value: bson.M{"players": players}}},
+		{{Key: "$unwind", Value: "$players"}},
+		{{Key: "$replaceRoot", Value: bson.M{"newRoot": "$players"}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":         "$userID",
+			"gamesPlayed": bson.M{"$sum": 1},
+			"wins":        isResult(winResult),
+			"losses":      isResult(lossResult),
+			"draws":       isResult(drawResult),
+		}}},
+		{{Key: "$addFields", Value: bson.M{
+			"winRate":  bson.M{"$divide": bson.A{"$wins", "$gamesPlayed"}},
+			"objectID": bson.M{"$convert": bson.M{"input": "$_id", "to": "objectId", "onError": nil, "onNull": nil}},
+		}}},
+		{{Key: "$lookup", Value: bson.M{"from": db.Collections.UserStats, "localField": "objectID", "foreignField": "userID", "as": "stats"}}},
+		{{Key: "$lookup", Value: bson.M{"from": db.Collections.Users, "localField": "objectID", "foreignField": "_id", "as": "user"}}},
+		{{Key: "$project", Value: bson.M{
+			"_id":         0,
+			"userID":      "$_id",
+			"username":    bson.M{"$ifNull": bson.A{bson.M{"$first": "$user.username"}, ""}},
+			"rating":      statsRating,
+			"gamesPlayed": 1,
+			"wins":        1,
+			"losses":      1,
+			"draws":       1,
+			"winRate":     1,
+		}}},
+		{{Key: "$sort", Value: bson.D{{Key: sort, Value: -1}, {Key: "userID", Value: 1}}}},
+		{{Key: "$skip", Value: (page - 1) * limit}},
+		{{Key: "$limit", Value: limit}},
+	}
+
+	collection := client.Database(db.Name).Collection(db.Collections.GameLogs)
+	cursor, err := collection.Aggregate(context.Background(), pipeline)
+	if err != nil {
+		return nil, err
+	}
+
+	err = cursor.All(context.Background(), &entries)
+	if err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+func FindUserBreakdown(client *mongo.Client, db config.DB, userID string) (*types.UserBreakdownResponse, error) {
+	var facets []struct {
+		Colors  []resultGroup   `bson:"colors"`
+		Reasons []resultGroup   `bson:"reasons"`
+		Summary []resultSummary `bson:"summary"`
+	}
+
+	filter := getFinishedGameFilter()
+	filter["$or"] = bson.A{bson.M{"whiteID": userID}, bson.M{"blackID": userID}}
+
+	pipeline := mongo.Pipeline{{{Key: "$match", Value: filter}}}
+	pipeline = append(pipeline, getUserResultStages(userID)...)
+	pipeline = append(pipeline, bson.D{{Key: "$facet", Value: bson.M{
+		"colors": bson.A{
+			bson.M{"$group": bson.M{"_id": bson.M{"color": "$color", "result": "$result"}, "count": bson.M{"$sum": 1}}},
+		},
+		"reasons": bson.A{
+			bson.M{"$group": bson.M{"_id": bson.M{"reason": "$reason", "result": "$result"}, "count": bson.M{"$sum": 1}}},
+		},
+		"summary": bson.A{
+			bson.M{"$group": bson.M{"_id": nil, "gamesPlayed": bson.M{"$sum": 1}, "averageMoveCount": bson.M{"$avg": "$moveCount"}}},
+		},
+	}}})
+
+	collection := client.Database(db.Name).Collection(db.Collections.GameLogs)
+	cursor, err := collection.Aggregate(context.Background(), pipeline)
+	if err != nil {
+		return nil, err
+	}
+
+	err = cursor.All(context.Background(), &facets)
+	if err != nil {
+		return nil, err
+	}
+
+	result := types.UserBreakdownResponse{
+		UserID:  userID,
+		Reasons: map[string]types.ResultCounts{},
+	}
+	if len(facets) == 0 {
+		return &result, nil
+	}
+
+	for _, group := range facets[0].Colors {
+		if group.ID.Color == types.White {
+			addResult(&result.White, group.ID.Result, group.Count)
+		} else {
+			addResult(&result.Black, group.ID.Result, group.Count)
+		}
+	}
+
+	for _, group := range facets[0].Reasons {
+		counts := result.Reasons[group.ID.Reason]
+		addResult(&counts, group.ID.Result, group.Count)
+		result.Reasons[group.ID.Reason] = counts
+	}
+
+	if len(facets[0].Summary) != 0 {
+		result.GamesPlayed = facets[0].Summary[0].GamesPlayed
+		result.AverageMoveCount = facets[0].Summary[0].AverageMoveCount
+	}
+
+	return &result, nil
+}
+
+func FindHeadToHead(client *mongo.Client, db config.DB, userID string, opponentID string) (*types.HeadToHeadResponse, error) {
+	var groups []resultGroup
+
+	filter := getFinishedGameFilter()
+	filter["$or"] = bson.A{
+		bson.M{"whiteID": userID, "blackID": opponentID},
+		bson.M{"whiteID": opponentID, "blackID": userID},
+	}
+
+	pipeline := mongo.Pipeline{{{Key: "$match", Value: filter}}}
+	pipeline = append(pipeline, getUserResultStages(userID)...)
+	pipeline = append(pipeline, bson.D{{Key: "$group", Value: bson.M{
+		"_id":   bson.M{"color": "$color", "result": "$result"},
+		"count": bson.M{"$sum": 1},
+	}}})
+
+	collection := client.Database(db.Name).Collection(db.Collections.GameLogs)
+	cursor, err := collection.Aggregate(context.Background(), pipeline)
+	if err != nil {
+		return nil, err
+	}
+
+	err = cursor.All(context.Background(), &groups)
+	if err != nil {
+		return nil, err
+	}
+
+	result := types.HeadToHeadResponse{
+		UserID:     userID,
+		OpponentID: opponentID,
+	}
+
+	for _, group := range groups {
+		result.GamesPlayed += group.Count
+		addResult(&result.Results, group.ID.Result, group.Count)
+		if group.ID.Color == types.White {
+			addResult(&result.White, group.ID.Result, group.Count)
+		} else {
+			addResult(&result.Black, group.ID.Result, group.Count)
+		}
+	}
+
+	return &result, nil
+}
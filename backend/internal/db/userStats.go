@@ -68,6 +68,10 @@ func FindUserStatsFromUserID(client *mongo.Client, db config.DB, userID string)
 }
 
 func UpdateUserStats(client *mongo.Client, db config.DB, userID string, userStatsUpdate types.UpdateUserStats) error {
+	return updateUserStats(context.Background(), client, db, userID, userStatsUpdate)
+}
+
+func updateUserStats(ctx context.Context, client *mongo.Client, db config.DB, userID string, userStatsUpdate types.UpdateUserStats) error {
 	id, err := primitive.ObjectIDFromHex(userID)
 	if err != nil {
 		return err
@@ -76,16 +80,20 @@ func UpdateUserStats(client *mongo.Client, db config.DB, userID string, userStat
 	filter := bson.M{"userID": id}
 	update := bson.M{
 		"$push": bson.M{
-			"gameLogs": userStatsUpdate.GameLog,
+			"gameLogs":      userStatsUpdate.GameLog,
+			"ratingHistory": userStatsUpdate.RatingRecord,
 		},
 		"$set": bson.M{
 			"gamesWon":    userStatsUpdate.GamesWon,
 			"gamesPlayed": userStatsUpdate.GamesPlayed,
+			"rating":      userStatsUpdate.Rating,
+			"rd":          userStatsUpdate.RD,
+			"volatility":  userStatsUpdate.Volatility,
 		},
 	}
 
 	collection := client.Database(db.Name).Collection(db.Collections.UserStats)
-	_, err = collection.UpdateOne(context.Background(), filter, update)
+	_, err = collection.UpdateOne(ctx, filter, update)
 	if err != nil {
 		return err
 	}
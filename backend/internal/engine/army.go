@@ -0,0 +1,346 @@
+package engine
+
+import (
+	"fmt"
+	"github.com/KainoaGardner/csc/internal/types"
+	"math/rand/v2"
+	"slices"
+)
+
+type armyPreset struct {
+	king    int
+	front   int   //fills the row nearest the place line
+	share   int   //front row gets at most 1/share of the money
+	pattern []int //every other square from the middle of the back row outward
+}
+
+var armyPresets = map[int]armyPreset{
+	types.ChessArmy: {
+		king:    types.King,
+		front:   types.Pawn,
+		share:   4,
+		pattern: []int{types.Queen, types.Bishop, types.Bishop, types.Knight, types.Knight, types.Rook, types.Rook},
+	},
+	types.ShogiArmy: {
+		king:    types.Ou,
+		front:   types.Fu,
+		share:   4,
+		pattern: []int{types.Kin, types.Kin, types.Gin, types.Gin, types.Kei, types.Kei, types.Kyou, types.Kyou, types.Hi, types.Kaku},
+	},
+	types.CheckersArmy: {
+		king:    types.Ou,
+		front:   types.Checker,
+		share:   1,
+		pattern: []int{types.Checker},
+	},
+}
+
+var randomArmyKings = []int{types.King, types.Ou}
+var randomArmyPieces = []int{
+	types.Pawn, types.Knight, types.Bishop, types.Rook, types.Queen,
+	types.Fu, types.Kyou, types.Kei, types.Gin, types.Kin, types.Kaku, types.Hi,
+	types.Checker,
+}
+
+// rows on turn's side of the place line from the back row forward
+func getArmyRows(game types.Game, turn int) []int {
+	var result []int
+
+	if turn == types.White {
+		for i := game.Board.Height - 1; i >= game.Board.PlaceLine; i-- {
+			result = append(result, i)
+		}
+	} else {
+		for i := 0; i < game.Board.PlaceLine; i++ {
+			result = append(result, i)
+		}
+	}
+
+	return result
+}
+
+// columns from the middle of the board outward
+func getArmyColumns(game types.Game) []int {
+	center := (game.Board.Width - 1) / 2
+	result := []int{center}
+
+	for i := 1; len(result) < game.Board.Width; i++ {
+		if center+i < game.Board.Width {
+			result = append(result, center+i)
+		}
+		if center-i >= 0 {
+			result = append(result, center-i)
+		}
+	}
+
+	return result
+}
+
+func getEmptyArmySquares(rows []int, game types.Game) []types.Vec2 {
+	var result []types.Vec2
+
+	for _, row := range rows {
+		for _, column := range getArmyColumns(game) {
+			if game.Board.Board[row][column] == nil {
+				result = append(result, types.Vec2{X: column, Y: row})
+			}
+		}
+	}
+
+	return result
+}
+
+// cost of pieceType in game, false if the game does not allow it
+func getArmyCost(pieceType int, game types.Game) (int, bool) {
+	cost, ok := GetPrices(game)[pieceType]
+	return cost, ok
+}
+
+func canBuyArmyPiece(pieceType int, money int, game types.Game) bool {
+	cost, ok := getArmyCost(pieceType, game)
+	return ok && cost <= money
+}
+
+func placeArmyPiece(pieceType int, pos types.Vec2, turn int, game *types.Game) error {
+	cost, err := getPieceCost(pieceType, *game)
+	if err != nil {
+		return err
+	}
+
+	place := types.Place{
+		Turn: turn,
+		Type: pieceType,
+		Pos:  pos,
+		Cost: cost,
+	}
+
+	return PlacePiece(place, game)
+}
+
+// the piece the pattern wants or the best cheaper one the preset uses
+func getArmyPiece(want int, preset armyPreset, money int, game types.Game) (int, bool) {
+	if canBuyArmyPiece(want, money, game) {
+		return want, true
+	}
+
+	prices := GetPrices(game)
+	pieces := append(slices.Clone(preset.pattern), preset.front)
+	slices.SortFunc(pieces, func(a int, b int) int {
+		return prices[b] - prices[a]
+	})
+
+	index := slices.IndexFunc(pieces, func(pieceType int) bool {
+		return canBuyArmyPiece(pieceType, money, game)
+	})
+	if index == -1 {
+		return 0, false
+	}
+
+	return pieces[index], true
+}
+
+// falls back to the other king when the game does not allow pieceType
+func placeArmyKing(pieceType int, squares []types.Vec2, turn int, game *types.Game) ([]types.Vec2, error) {
+	if checkHasKing(turn, *game) == nil {
+		return squares, nil
+	}
+
+	if len(squares) == 0 {
+		return squares, fmt.Errorf("No room left for a king")
+	}
+
+	_, ok := getArmyCost(pieceType, *game)
+	if !ok && pieceType == types.King {
+		pieceType = types.Ou
+	} else if !ok {
+		pieceType = types.King
+	}
+	if !canBuyArmyPiece(pieceType, game.Money[turn], *game) {
+		return squares, fmt.Errorf("Not enough money for a king")
+	}
+
+	return squares[1:], placeArmyPiece(pieceType, squares[0], turn, game)
+}
+
+func fillArmyFrontRow(pieceType int, money int, front int, turn int, game *types.Game) error {
+	cost, ok := getArmyCost(pieceType, *game)
+	if !ok {
+		return nil
+	}
+
+	for _, pos := range getEmptyArmySquares([]int{front}, *game) {
+		if cost > money || cost > game.Money[turn] {
+			return nil
+		}
+
+		err := placeArmyPiece(pieceType, pos, turn, game)
+		if err != nil {
+			return err
+		}
+		money -= cost
+	}
+
+	return nil
+}
+
+// places around what turn already has on the board using turn's money left
+func placeArmy(preset int, turn int, game *types.Game) error {
+	if preset == types.RandomArmy {
+		return placeRandomArmy(turn, game)
+	}
+
+	army, ok := armyPresets[preset]
+	if !ok {
+		return fmt.Errorf("Invalid army preset")
+	}
+
+	rows := getArmyRows(*game, turn)
+	squares, err := placeArmyKing(army.king, getEmptyArmySquares(rows, *game), turn, game)
+	if err != nil {
+		return err
+	}
+
+	front := -1
+	if len(rows) > 1 {
+		front = rows[len(rows)-1]
+		err = fillArmyFrontRow(army.front, game.Money[turn]/army.share, front, turn, game)
+		if err != nil {
+			return err
+		}
+	}
+
+	i := 0
+	for _, pos := range squares {
+		if pos.Y == front {
+			continue
+		}
+
+		pieceType, ok := getArmyPiece(army.pattern[i%len(army.pattern)], army, game.Money[turn], *game)
+		if !ok {
+			break
+		}
+
+		err = placeArmyPiece(pieceType, pos, turn, game)
+		if err != nil {
+			return err
+		}
+		i++
+	}
+
+	//money left over goes to the front row
+	if front != -1 {
+		return fillArmyFrontRow(army.front, game.Money[turn], front, turn, game)
+	}
+
+	return nil
+}
+
+func placeRandomArmy(turn int, game *types.Game) error {
+	squares := getEmptyArmySquares(getArmyRows(*game, turn), *game)
+	king := randomArmyKings[rand.IntN(len(randomArmyKings))]
+
+	squares, err := placeArmyKing(king, squares, turn, game)
+	if err != nil {
+		return err
+	}
+
+	rand.Shuffle(len(squares), func(i int, j int) {
+		squares[i], squares[j] = squares[j], squares[i]
+	})
+
+	for _, pos := range squares {
+		var pieces []int
+		for _, pieceType := range randomArmyPieces {
+			if canBuyArmyPiece(pieceType, game.Money[turn], *game) {
+				pieces = append(pieces, pieceType)
+			}
+		}
+		if len(pieces) == 0 {
+			return nil
+		}
+
+		err = placeArmyPiece(pieces[rand.IntN(len(pieces))], pos, turn, game)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func setupArmyResponse(preset int, placed bool, records []types.PlacementRecord, game types.Game) (types.ArmyResponse, error) {
+	var result types.ArmyResponse
+
+	fen, err := ConvertBoardToString(game)
+	if err != nil {
+		return result, err
+	}
+
+	result.ID = game.ID
+	result.Preset = preset
+	result.Pieces = []types.PostPlace{}
+	result.FEN = fen
+	result.Money = game.Money
+	result.Placed = placed
+
+	for _, record := range records {
+		result.Pieces = append(result.Pieces, types.PostPlace{
+			Position: record.Position,
+			Type:     record.Type,
+			Place:    types.CreatePlaceEnum,
+		})
+		result.Cost += record.Cost
+	}
+
+	return result, nil
+}
+
+// the army placeArmy would add, the game itself is left alone
+func ProposeArmy(preset int, turn int, game types.Game) (types.ArmyResponse, error) {
+	err := checkGameState(types.PlaceState, game.State)
+	if err != nil {
+		return types.ArmyResponse{}, err
+	}
+
+	gameCopy := copyGame(game)
+	gameCopy.Placements = nil
+
+	err = placeArmy(preset, turn, gameCopy)
+	if err != nil {
+		return types.ArmyResponse{}, err
+	}
+
+	return setupArmyResponse(preset, false, gameCopy.Placements, *gameCopy)
+}
+
+// proposes on an empty board set up like a new game
+func ProposeArmyForBoard(proposal types.PostArmyProposal) (types.ArmyResponse, error) {
+	if proposal.Turn != types.White && proposal.Turn != types.Black {
+		return types.ArmyResponse{}, fmt.Errorf("Invalid turn")
+	}
+
+	postGame := types.PostGame{
+		Width:     proposal.Width,
+		Height:    proposal.Height,
+		PlaceLine: proposal.PlaceLine,
+		Money:     [2]int{proposal.Money, proposal.Money},
+	}
+	game, err := SetupNewGame(postGame, "")
+	if err != nil {
+		return types.ArmyResponse{}, err
+	}
+	game.State = types.PlaceState
+
+	return ProposeArmy(proposal.Preset, proposal.Turn, *game)
+}
+
+func PlaceArmy(preset int, turn int, game *types.Game) (types.ArmyResponse, error) {
+	before := len(game.Placements)
+
+	err := placeArmy(preset, turn, game)
+	if err != nil {
+		return types.ArmyResponse{}, err
+	}
+
+	return setupArmyResponse(preset, true, game.Placements[before:], *game)
+}
@@ -0,0 +1,147 @@
+package engine
+
+import (
+	"testing"
+
+	"github.com/KainoaGardner/csc/internal/types"
+)
+
+func setupTestPlaceGame(t *testing.T, money int) *types.Game {
+	t.Helper()
+
+	postGame := types.PostGame{
+		Width:     8,
+		Height:    8,
+		PlaceLine: 4,
+		Money:     [2]int{money, money},
+		StartTime: [2]int64{600, 600},
+	}
+	game, err := SetupNewGame(postGame, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	game.State = types.PlaceState
+
+	return game
+}
+
+func TestPlaceArmyPresets(t *testing.T) {
+	for _, preset := range []int{types.ChessArmy, types.ShogiArmy, types.CheckersArmy, types.RandomArmy} {
+		for _, turn := range []int{types.White, types.Black} {
+			game := setupTestPlaceGame(t, 300)
+
+			proposal, err := ProposeArmy(preset, turn, *game)
+			if err != nil {
+				t.Fatalf("preset %d: %v", preset, err)
+			}
+			if len(game.Placements) != 0 || game.Money[turn] != 300 {
+				t.Fatalf("preset %d: proposing should not change the game", preset)
+			}
+
+			army, err := PlaceArmy(preset, turn, game)
+			if err != nil {
+				t.Fatalf("preset %d: %v", preset, err)
+			}
+			if preset != types.RandomArmy && army.FEN != proposal.FEN {
+				t.Errorf("preset %d: placed %s, proposed %s", preset, army.FEN, proposal.FEN)
+			}
+			if army.Cost != 300-game.Money[turn] || len(army.Pieces) != len(game.Placements) {
+				t.Errorf("preset %d: cost %d with %d left, %d pieces for %d placements", preset, army.Cost, game.Money[turn], len(army.Pieces), len(game.Placements))
+			}
+
+			err = checkHasKing(turn, *game)
+			if err != nil {
+				t.Errorf("preset %d: %v", preset, err)
+			}
+
+			for i, row := range game.Board.Board {
+				onSide := (turn == types.White) == (i >= game.Board.PlaceLine)
+				for _, piece := range row {
+					if piece != nil && (piece.Owner != turn || !onSide) {
+						t.Errorf("preset %d: %+v on row %d", preset, piece, i)
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestChessArmyLayout(t *testing.T) {
+	game := setupTestPlaceGame(t, 300)
+
+	army, err := PlaceArmy(types.ChessArmy, types.White, game)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []types.PostPlace{
+		{Position: "d1", Type: types.King},
+		{Position: "d4", Type: types.Pawn},
+		{Position: "e4", Type: types.Pawn},
+	}
+	for i, place := range expected {
+		if army.Pieces[i].Position != place.Position || army.Pieces[i].Type != place.Type {
+			t.Errorf("piece %d got %+v, expected %+v", i, army.Pieces[i], place)
+		}
+	}
+
+	queen := game.Board.Board[7][4]
+	if queen == nil || queen.Type != types.Queen {
+		t.Errorf("expected a queen next to the king, got %+v", queen)
+	}
+}
+
+func TestPlaceArmyKeepsPieces(t *testing.T) {
+	game := setupTestPlaceGame(t, 100)
+
+	place, err := SetupPlace(types.PostPlace{Position: "a1", Type: types.Ou}, types.White, *game)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = PlacePiece(place, game)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	army, err := PlaceArmy(types.ChessArmy, types.White, game)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, piece := range army.Pieces {
+		if piece.Type == types.King || piece.Type == types.Ou || piece.Position == "a1" {
+			t.Errorf("army should build around the placed ou, got %+v", piece)
+		}
+	}
+	if army.Cost != 55-game.Money[types.White] {
+		t.Errorf("army cost %d should come out of the 55 left", army.Cost)
+	}
+}
+
+func TestProposeArmyForBoardErrors(t *testing.T) {
+	cases := []struct {
+		name     string
+		proposal types.PostArmyProposal
+	}{
+		{"invalid preset", types.PostArmyProposal{Preset: 99, Width: 8, Height: 8, PlaceLine: 4, Money: 100}},
+		{"invalid turn", types.PostArmyProposal{Width: 8, Height: 8, PlaceLine: 4, Money: 100, Turn: 2}},
+		{"place line off the board", types.PostArmyProposal{Width: 8, Height: 8, PlaceLine: 8, Money: 100}},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			_, err := ProposeArmyForBoard(c.proposal)
+			if err == nil {
+				t.Errorf("proposal should fail")
+			}
+		})
+	}
+
+	army, err := ProposeArmyForBoard(types.PostArmyProposal{Preset: types.ShogiArmy, Width: 9, Height: 9, PlaceLine: 4, Money: 200, Turn: types.Black})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(army.Pieces) == 0 || army.Pieces[0].Type != types.Ou || army.Pieces[0].Position != "e9" {
+		t.Errorf("expected an ou on e9 first, got %+v", army.Pieces)
+	}
+}
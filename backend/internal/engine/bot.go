@@ -0,0 +1,336 @@
+package engine
+
+import (
+	"errors"
+	"fmt"
+	"github.com/KainoaGardner/csc/internal/store"
+	"github.com/KainoaGardner/csc/internal/types"
+	"math/rand/v2"
+	"slices"
+	"time"
+)
+
+type botLevel struct {
+	depth int
+	time  time.Duration //most a single move may take
+}
+
+var botLevels = map[int]botLevel{
+	types.BotEasy:   {depth: 1, time: time.Second},
+	types.BotMedium: {depth: 2, time: 2 * time.Second},
+	types.BotHard:   {depth: 3, time: 5 * time.Second},
+	types.BotExpert: {depth: 4, time: 10 * time.Second},
+}
+
+const botMateScore = 1000000
+
+var errBotTimeout = errors.New("Bot ran out of time")
+
+func checkBotLevel(level int) error {
+	if level == types.NoBot {
+		return nil
+	}
+
+	_, ok := botLevels[level]
+	if !ok {
+		return fmt.Errorf("Invalid bot level")
+	}
+
+	return nil
+}
+
+func GetBotTurn(game types.Game) (int, bool) {
+	if game.BotLevel == types.NoBot {
+		return -1, false
+	}
+
+	turn, err := GetTurnFromID(game, types.BotID)
+	if err != nil {
+		return -1, false
+	}
+
+	return turn, true
+}
+
+func IsBotTurn(game types.Game) bool {
+	turn, ok := GetBotTurn(game)
+	return ok && game.State == types.MoveState && game.Winner == nil && game.Turn == turn
+}
+
+// promoted pieces are worth what they move like
+func getBotPieceValue(pieceType int) int {
+	switch pieceType {
+	case types.To, types.NariKyou, types.NariKei, types.NariGin:
+		return types.PieceToCost[types.Kin]
+	case types.Uma:
+		return types.PieceToCost[types.Kaku] + types.PieceToCost[types.Gin]/2
+	case types.Ryuu:
+		return types.PieceToCost[types.Hi] + types.PieceToCost[types.Gin]/2
+	case types.CheckerKing:
+		return types.PieceToCost[types.Checker] * 2
+	default:
+		return types.PieceToCost[pieceType]
+	}
+}
+
+// material from turn's side including pieces in hand
+func evaluateBotGame(game types.Game, turn int) int {
+	result := 0
+
+	for _, row := range game.Board.Board {
+		for _, piece := range row {
+			if piece == nil {
+				continue
+			}
+
+			value := getBotPieceValue(piece.Type)
+			if piece.Owner == turn {
+				result += value
+			} else {
+				result -= value
+			}
+		}
+	}
+
+	for i, count := range game.Mochigoma {
+		value := getBotPieceValue(types.ShogiMochiPieceToDropPiece[i%types.MochigomaBlackOffset])
+		if (i < types.MochigomaBlackOffset) == (turn == types.White) {
+			result += value * count
+		} else {
+			result -= value * count
+		}
+	}
+
+	for i, count := range game.Hand {
+		value := getBotPieceValue(types.HandPieceToDropPiece[i%types.HandBlackOffset])
+		if (i < types.HandBlackOffset) == (turn == types.White) {
+			result += value * count
+		} else {
+			result -= value * count
+		}
+	}
+
+	return result
+}
+
+// captures first with the most valuable victim, then promotions
+func getBotMoveOrder(move types.Move, game types.Game) int {
+	result := 0
+
+	if move.Drop == nil {
+		piece := game.Board.Board[move.Start.Y][move.Start.X]
+		takePiece := getTakePiece(move, game, piece, getMoveDirection(game))
+		if takePiece != nil && takePiece.Owner != game.Turn {
+			result += getBotPieceValue(takePiece.Type) * 10
+		}
+	}
+
+	if move.Promote != nil {
+		result += *move.Promote
+	}
+
+	return result
+}
+
+func orderBotMoves(moves []types.Move, game types.Game) {
+	slices.SortStableFunc(moves, func(a types.Move, b types.Move) int {
+		return getBotMoveOrder(b, game) - getBotMoveOrder(a, game)
+	})
+}
+
+func applyBotMove(move types.Move, game types.Game) (*types.Game, error) {
+	gameCopy := copyGame(game)
+	gameCopy.PositionHistory = copyPositionHistory(game.PositionHistory)
+
+	err := applyMove(move, gameCopy)
+	if err != nil {
+		return nil, err
+	}
+
+	return gameCopy, nil
+}
+
+type botSearch struct {
+	turn     int
+	deadline time.Time
+	timed    bool
+}
+
+// the bot maximizes, checker jump chains keep the same side to move
+func (s *botSearch) search(game types.Game, depth int, ply int, alpha int, beta int) (int, error) {
+	if game.State == types.OverState {
+		if game.Winner == nil || *game.Winner == types.Tie {
+			return 0, nil
+		}
+		if *game.Winner == s.turn {
+			return botMateScore - ply, nil
+		}
+		return ply - botMateScore, nil
+	}
+
+	if depth == 0 {
+		return evaluateBotGame(game, s.turn), nil
+	}
+
+	if s.timed && time.Now().After(s.deadline) {
+		return 0, errBotTimeout
+	}
+
+	moves := LegalMoves(game)
+	if len(moves) == 0 {
+		return evaluateBotGame(game, s.turn), nil
+	}
+	orderBotMoves(moves, game)
+
+	maximize := game.Turn == s.turn
+	result := botMateScore + 1
+	if maximize {
+		result = -result
+	}
+
+	for _, move := range moves {
+		child, err := applyBotMove(move, game)
+		if err != nil {
+			return 0, err
+		}
+
+		score, err := s.search(*child, depth-1, ply+1, alpha, beta)
+		if err != nil {
+			return 0, err
+		}
+
+		if maximize {
+			result = max(result, score)
+			alpha = max(alpha, score)
+		} else {
+			result = min(result, score)
+			beta = min(beta, score)
+		}
+
+		if alpha >= beta {
+			break
+		}
+	}
+
+	return result, nil
+}
+
+func (s *botSearch) searchRoot(game types.Game, moves []types.Move, depth int) (types.Move, error) {
+	result := moves[0]
+	alpha := -botMateScore - 1
+
+	for _, move := range moves {
+		child, err := applyBotMove(move, game)
+		if err != nil {
+			return result, err
+		}
+
+		score, err := s.search(*child, depth-1, 1, alpha, botMateScore+1)
+		if err != nil {
+			return result, err
+		}
+
+		if score > alpha {
+			alpha = score
+			result = move
+		}
+	}
+
+	return result, nil
+}
+
+// the level time capped to a share of what is left on the clock
+func getBotTimeLimit(level botLevel, game types.Game) time.Duration {
+	allowance := time.Duration(getTimeAllowance(game)) * time.Millisecond
+	return min(level.time, allowance/20+moveTimeBuffer/2)
+}
+
+// deepens one ply at a time and keeps the last search that finished
+func GetBotMove(game types.Game) (types.Move, error) {
+	var result types.Move
+
+	level, ok := botLevels[game.BotLevel]
+	if !ok {
+		return result, fmt.Errorf("Invalid bot level")
+	}
+
+	moves := LegalMoves(game)
+	if len(moves) == 0 {
+		return result, fmt.Errorf("No legal moves")
+	}
+
+	//equal moves are played in a random order
+	rand.Shuffle(len(moves), func(i int, j int) {
+		moves[i], moves[j] = moves[j], moves[i]
+	})
+	orderBotMoves(moves, game)
+	result = moves[0]
+
+	search := botSearch{
+		turn:     game.Turn,
+		deadline: time.Now().Add(getBotTimeLimit(level, game)),
+	}
+
+	for depth := 1; depth <= level.depth; depth++ {
+		search.timed = depth > 1
+
+		move, err := search.searchRoot(game, moves, depth)
+		if errors.Is(err, errBotTimeout) {
+			break
+		}
+		if err != nil {
+			return result, err
+		}
+		result = move
+
+		//the best move is searched first on the next depth
+		index := slices.IndexFunc(moves, func(m types.Move) bool {
+			return m == move
+		})
+		moves = slices.Delete(moves, index, index+1)
+		moves = slices.Insert(moves, 0, move)
+	}
+
+	return result, nil
+}
+
+// places and readies the bot once a player has joined
+func setupBotPlacement(game *types.Game) error {
+	turn, ok := GetBotTurn(*game)
+	if !ok || game.State != types.PlaceState {
+		return nil
+	}
+
+	err := placeArmy(types.ChessArmy, turn, game)
+	if err != nil {
+		return err
+	}
+
+	return ReadyPlayer(true, turn, game)
+}
+
+// plays the move the bot picks from game, game has to be the bot's turn
+func BotMoveCase(game types.Game, store store.Store) (*types.Game, types.PostMove, string, error) {
+	var result types.PostMove
+
+	if !IsBotTurn(game) {
+		return nil, result, "", fmt.Errorf("Not the bot's turn")
+	}
+
+	move, err := GetBotMove(game)
+	if err != nil {
+		return nil, result, "", err
+	}
+
+	result.Move, err = ConvertMoveToString(move, game)
+	if err != nil {
+		return nil, result, "", err
+	}
+
+	gameResult, fen, err := MoveCase(game.ID.Hex(), types.BotID, result, store)
+	if err != nil {
+		return nil, result, "", err
+	}
+
+	return gameResult, result, fen, nil
+}
@@ -0,0 +1,191 @@
+package engine
+
+import (
+	"testing"
+	"time"
+
+	"github.com/KainoaGardner/csc/internal/store"
+	"github.com/KainoaGardner/csc/internal/types"
+)
+
+func TestBotPlacement(t *testing.T) {
+	postGame := types.PostGame{
+		Width:     8,
+		Height:    8,
+		PlaceLine: 4,
+		Money:     [2]int{200, 200},
+		StartTime: [2]int64{600, 600},
+		BotLevel:  types.BotEasy,
+	}
+	game, err := SetupNewGame(postGame, "white")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if game.BlackID != types.BotID || game.Public {
+		t.Fatalf("got black %s public %v, expected a private game against the bot", game.BlackID, game.Public)
+	}
+
+	err = SetupJoinGame(game, "white")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if game.State != types.PlaceState || !game.Ready[types.Black] || game.Ready[types.White] {
+		t.Fatalf("got state %d ready %v, expected only the bot ready", game.State, game.Ready)
+	}
+	if game.Money[types.Black] < 0 || game.Money[types.Black] >= types.PieceToCost[types.Pawn] {
+		t.Errorf("bot should spend its money, %d left", game.Money[types.Black])
+	}
+
+	spent := 0
+	for _, record := range game.Placements {
+		spent += record.Cost
+	}
+	if spent != postGame.Money[types.Black]-game.Money[types.Black] {
+		t.Errorf("placements cost %d, money went down %d", spent, postGame.Money[types.Black]-game.Money[types.Black])
+	}
+
+	for i, row := range game.Board.Board {
+		for _, piece := range row {
+			if piece != nil && (piece.Owner != types.Black || i >= game.Board.PlaceLine) {
+				t.Errorf("bot placed %+v on row %d", piece, i)
+			}
+		}
+	}
+
+	_, err = SetupNewGame(types.PostGame{Width: 8, Height: 8, PlaceLine: 4, Money: [2]int{200, 200}, BotLevel: 99}, "white")
+	if err == nil {
+		t.Errorf("bot level 99 should not exist")
+	}
+}
+
+func TestGetBotMove(t *testing.T) {
+	cases := []struct {
+		name     string
+		fen      string
+		level    int
+		expected string
+		avoid    bool //expected is the one move it should not play
+	}{
+		{"takes the checking queen", "4ck*3/8/8/cq-7/8/8/8/CR*3CK*3 0/0/0/0/0/0/0/0/0/0/0/0/0/0 w - - 0 0 600000/600000", types.BotEasy, "a1,a5", false},
+		{"mates in one", "4ck*3/CR-7/8/8/8/8/8/4CK*2CR* 0/0/0/0/0/0/0/0/0/0/0/0/0/0 w - - 0 0 600000/600000", types.BotMedium, "h1,h8", false},
+		{"takes a free rook", "4ck*3/8/8/3cr-4/8/3CQ-4/8/4CK*3 0/0/0/0/0/0/0/0/0/0/0/0/0/0 w - - 0 0 600000/600000", types.BotMedium, "d3,d5", false},
+		{"does not take a defended rook", "4ck*3/8/2cp-5/3cr-4/8/3CQ-4/8/4CK*3 0/0/0/0/0/0/0/0/0/0/0/0/0/0 w - - 0 0 600000/600000", types.BotMedium, "d3,d5", true},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			game := loadTestGame(t, c.fen)
+			game.BotLevel = c.level
+
+			move, err := GetBotMove(*game)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			moveString, err := ConvertMoveToString(move, *game)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if c.avoid && moveString == c.expected {
+				t.Errorf("should not play %s", moveString)
+			}
+			if !c.avoid && moveString != c.expected {
+				t.Errorf("got %s, expected %s", moveString, c.expected)
+			}
+		})
+	}
+}
+
+func TestBotMoveCase(t *testing.T) {
+	gameStore := store.NewMemoryStore()
+
+	game := loadTestGame(t, "4ck*3/4cp*3/8/8/8/8/4CP*3/4CK*3 0/0/0/0/0/0/0/0/0/0/0/0/0/0 b - - 0 0 600000/600000")
+	game.WhiteID = "white"
+	game.BlackID = types.BotID
+	game.BotLevel = types.BotHard
+	game.LastMoveTime = time.Now().UTC()
+
+	gameID, err := gameStore.CreateGame(game)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = gameStore.CreateGameLog(SetupGameLog(*game, ""))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	stored, err := gameStore.FindGame(gameID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !IsBotTurn(*stored) {
+		t.Fatalf("should be the bot's turn")
+	}
+
+	result, postMove, _, err := BotMoveCase(*stored, gameStore)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Turn != types.White || IsBotTurn(*result) {
+		t.Errorf("bot move %s should pass the turn to white", postMove.Move)
+	}
+
+	_, _, _, err = BotMoveCase(*result, gameStore)
+	if err == nil {
+		t.Errorf("bot should not move on white's turn")
+	}
+}
+
+func TestBotGameUnrated(t *testing.T) {
+	gameStore := store.NewMemoryStore()
+
+	game := loadTestGame(t, "4ck*3/4cp*3/8/8/8/8/4CP*3/4CK*3 0/0/0/0/0/0/0/0/0/0/0/0/0/0 b - - 0 0 600000/600000")
+	game.WhiteID = "white"
+	game.BlackID = types.BotID
+	game.BotLevel = types.BotEasy
+	game.State = types.MoveState
+	game.LastMoveTime = time.Now().UTC()
+
+	gameID, err := gameStore.CreateGame(game)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = gameStore.CreateGameLog(SetupGameLog(*game, ""))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	stored, err := gameStore.FindGame(gameID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _, _, err = BotMoveCase(*stored, gameStore)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	over, err := ResignCase(gameID, "white", gameStore)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = GameOverCase(*over, gameID, gameStore)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	entries, err := gameStore.FindLeaderboard("wins", 1, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 0 {
+		t.Errorf("bot games should stay off the leaderboard, got %+v", entries)
+	}
+
+	breakdown, err := gameStore.FindUserBreakdown("white")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if breakdown.GamesPlayed != 0 {
+		t.Errorf("bot games should not count toward stats, got %+v", breakdown)
+	}
+}
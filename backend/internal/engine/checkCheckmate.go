@@ -113,14 +113,14 @@ func getAllPossibleDrops(game types.Game) []types.Vec2 {
 
 	for i := 0; i < game.Board.Height; i++ {
 		for j := 0; j < game.Board.Width; j++ {
-			for k := 0; k < 7; k++ {
+			for _, k := range getAllDrops() {
 				move := types.Move{}
 				move.End.X = j
 				move.End.Y = i
 				move.Drop = &k
 				piece := types.Piece{}
 				piece.Owner = game.Turn
-				piece.Type = types.Fu + k
+				piece.Type, _ = getDropPieceType(k)
 				err := checkValidDrop(move, piece, game)
 				if err == nil {
 					possibleDrops = append(possibleDrops, move.End)
@@ -0,0 +1,173 @@
+package engine
+
+import (
+	"errors"
+	"fmt"
+	"github.com/KainoaGardner/csc/internal/config"
+	"github.com/KainoaGardner/csc/internal/store"
+	"github.com/KainoaGardner/csc/internal/types"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"log"
+	"sync"
+	"time"
+)
+
+type GameOverFunc func(*types.Game, string, string, store.Store, config.Config) bool
+
+type clockScheduler struct {
+	timers   map[string]*time.Timer //gameID -> flag fall timer
+	games    map[string]types.Game  //gameID -> clock fields of the running game
+	mutex    sync.Mutex
+	store    store.Store
+	config   config.Config
+	gameOver GameOverFunc
+}
+
+var errClockStopped = fmt.Errorf("Clock no longer needs to fall")
+
+// nil until StartClockScheduler is called so engine tests never arm timers
+var clock *clockScheduler
+
+func StartClockScheduler(store store.Store, config config.Config, gameOver GameOverFunc) error {
+	clock = &clockScheduler{
+		timers:   make(map[string]*time.Timer),
+		games:    make(map[string]types.Game),
+		store:    store,
+		config:   config,
+		gameOver: gameOver,
+	}
+
+	games, err := store.ListGamesInState(types.MoveState)
+	if err != nil {
+		return err
+	}
+
+	for _, game := range games {
+		armClock(*game)
+	}
+
+	log.Printf("Clock scheduler started with %d running games", len(games))
+	return nil
+}
+
+func getClockDeadline(game types.Game) time.Time {
+	allowance := time.Duration(getTimeAllowance(game)) * time.Millisecond
+	return game.LastMoveTime.Add(moveTimeBuffer + allowance)
+}
+
+// arms the flag fall timer for the side to move, or cancels it once the game is not running
+func armClock(game types.Game) {
+	if clock == nil || game.ID == primitive.NilObjectID {
+		return
+	}
+
+	gameID := game.ID.Hex()
+	if game.State != types.MoveState {
+		CancelClock(gameID)
+		return
+	}
+
+	wait := time.Until(getClockDeadline(game))
+
+	clock.mutex.Lock()
+	defer clock.mutex.Unlock()
+
+	timer, ok := clock.timers[gameID]
+	if ok {
+		timer.Stop()
+	}
+
+	var newTimer *time.Timer
+	newTimer = time.AfterFunc(wait, func() {
+		flagFall(gameID, newTimer)
+	})
+	clock.timers[gameID] = newTimer
+	clock.games[gameID] = getClockGame(game)
+}
+
+// only what GetClock reads
+func getClockGame(game types.Game) types.Game {
+	var result types.Game
+	result.State = game.State
+	result.Turn = game.Turn
+	result.Time = game.Time
+	result.Periods = game.Periods
+	result.TimeControl = game.TimeControl
+	result.LastMoveTime = game.LastMoveTime
+
+	return result
+}
+
+// every running game is armed at startup and on each ready, move and takeback
+// so this is the current clock without loading the game
+func GetRunningClock(gameID string) (types.ClockResponse, bool) {
+	if clock == nil {
+		return types.ClockResponse{}, false
+	}
+
+	clock.mutex.Lock()
+	game, ok := clock.games[gameID]
+	clock.mutex.Unlock()
+	if !ok {
+		return types.ClockResponse{}, false
+	}
+
+	return GetClock(game), true
+}
+
+func CancelClock(gameID string) {
+	if clock == nil {
+		return
+	}
+
+	clock.mutex.Lock()
+	defer clock.mutex.Unlock()
+
+	timer, ok := clock.timers[gameID]
+	if ok {
+		timer.Stop()
+		delete(clock.timers, gameID)
+	}
+	delete(clock.games, gameID)
+}
+
+func flagFall(gameID string, timer *time.Timer) {
+	clock.mutex.Lock()
+	if clock.timers[gameID] != timer {
+		clock.mutex.Unlock()
+		return
+	}
+	delete(clock.timers, gameID)
+	delete(clock.games, gameID)
+	clock.mutex.Unlock()
+
+	//the stored game is the source of truth, a move may have landed first
+	game, err := updateGameCase(gameID, clock.store, func(game *types.Game) error {
+		if game.State != types.MoveState {
+			return errClockStopped
+		}
+
+		if time.Now().UTC().Before(getClockDeadline(*game)) {
+			armClock(*game)
+			return errClockStopped
+		}
+
+		setupTimeLoss(game)
+		return nil
+	}, clock.store.GameMoveUpdate)
+	if errors.Is(err, errClockStopped) {
+		return
+	}
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	//the side that was to move lost on time
+	playerID := game.WhiteID
+	if *game.Winner == types.White {
+		playerID = game.BlackID
+	}
+
+	clock.gameOver(game, gameID, playerID, clock.store, clock.config)
+}
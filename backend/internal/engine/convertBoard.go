@@ -3,6 +3,7 @@ package engine
 import (
 	"fmt"
 	"github.com/KainoaGardner/csc/internal/types"
+	"github.com/KainoaGardner/csc/internal/utils"
 	"strconv"
 	"strings"
 )
@@ -131,6 +132,13 @@ func convertMochigomaToString(game types.Game) string {
 		result += strconv.Itoa(game.Mochigoma[i]) + "/"
 	}
 
+	//crazyhouse hand counts only follow once something is in hand
+	if !checkHandEmpty(game) {
+		for i := 0; i < len(game.Hand); i++ {
+			result += strconv.Itoa(game.Hand[i]) + "/"
+		}
+	}
+
 	return result[:len(result)-1]
 }
 
@@ -183,3 +191,259 @@ func getTimeString(game types.Game) string {
 
 	return result
 }
+
+func ConvertStringToBoard(fen string) (*types.Game, error) {
+	fields := strings.Fields(fen)
+	if len(fields) != 8 {
+		return nil, fmt.Errorf("Fen must have 8 fields, got %d", len(fields))
+	}
+
+	game := types.Game{}
+	game.PositionHistory = map[string]types.PositionRecord{}
+	game.State = types.MoveState
+
+	err := convertStringToPiecePosition(fields[0], &game)
+	if err != nil {
+		return nil, err
+	}
+
+	err = convertStringToMochigoma(fields[1], &game)
+	if err != nil {
+		return nil, err
+	}
+
+	turn, err := getTurnFromString(fields[2])
+	if err != nil {
+		return nil, err
+	}
+	game.Turn = turn
+
+	enPassant, err := getOptionalPositionFromString(fields[3], game)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid en passant field: %v", err)
+	}
+	game.EnPassant = enPassant
+
+	checkerJump, err := getOptionalPositionFromString(fields[4], game)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid checker jump field: %v", err)
+	}
+	game.CheckerJump = checkerJump
+
+	halfMoveCount, err := getCountFromString(fields[5])
+	if err != nil {
+		return nil, fmt.Errorf("Invalid half move count: %v", err)
+	}
+	game.HalfMoveCount = halfMoveCount
+
+	moveCount, err := getCountFromString(fields[6])
+	if err != nil {
+		return nil, fmt.Errorf("Invalid move count: %v", err)
+	}
+	game.MoveCount = moveCount
+
+	err = convertStringToTime(fields[7], &game)
+	if err != nil {
+		return nil, err
+	}
+
+	return &game, nil
+}
+
+func convertStringToPiecePosition(piecePositionString string, game *types.Game) error {
+	rows := strings.Split(piecePositionString, "/")
+
+	width := -1
+	board := make([][]*types.Piece, len(rows))
+	for i, row := range rows {
+		boardRow, err := convertStringToBoardRow(row)
+		if err != nil {
+			return fmt.Errorf("Fen row %d: %v", i+1, err)
+		}
+
+		if width == -1 {
+			width = len(boardRow)
+		} else if len(boardRow) != width {
+			return fmt.Errorf("Fen row %d has width %d, expected %d", i+1, len(boardRow), width)
+		}
+
+		board[i] = boardRow
+	}
+
+	if width <= 0 {
+		return fmt.Errorf("Fen board cannot be empty")
+	}
+
+	game.Board.Width = width
+	game.Board.Height = len(rows)
+	game.Board.Board = board
+
+	return nil
+}
+
+func convertStringToBoardRow(row string) ([]*types.Piece, error) {
+	var result []*types.Piece
+
+	i := 0
+	for i < len(row) {
+		char := row[i]
+
+		if utils.IsDigit(char) {
+			j := i
+			for j < len(row) && utils.IsDigit(row[j]) {
+				j++
+			}
+
+			emptyCount, err := strconv.Atoi(row[i:j])
+			if err != nil {
+				return nil, err
+			}
+			if emptyCount <= 0 {
+				return nil, fmt.Errorf("Empty count must be positive at column %d", i+1)
+			}
+
+			for k := 0; k < emptyCount; k++ {
+				result = append(result, nil)
+			}
+
+			i = j
+			continue
+		}
+
+		if i+3 > len(row) {
+			return nil, fmt.Errorf("Incomplete piece %q at column %d", row[i:], i+1)
+		}
+
+		piece, err := convertStringToPiece(row[i : i+3])
+		if err != nil {
+			return nil, fmt.Errorf("%v at column %d", err, i+1)
+		}
+		result = append(result, piece)
+
+		i += 3
+	}
+
+	return result, nil
+}
+
+func convertStringToPiece(pieceString string) (*types.Piece, error) {
+	piece := types.Piece{}
+
+	pieceType := pieceString[:2]
+	switch pieceType {
+	case strings.ToUpper(pieceType):
+		piece.Owner = types.White
+	case strings.ToLower(pieceType):
+		piece.Owner = types.Black
+	default:
+		return nil, fmt.Errorf("Piece %q has mixed case", pieceType)
+	}
+
+	pieceInt, ok := types.FenStringToPiece[strings.ToUpper(pieceType)]
+	if !ok {
+		return nil, fmt.Errorf("Unknown piece %q", pieceType)
+	}
+	piece.Type = pieceInt
+
+	switch pieceString[2] {
+	case '*':
+		piece.Moved = false
+	case '-':
+		piece.Moved = true
+	default:
+		return nil, fmt.Errorf("Piece %q must end in * or -", pieceString)
+	}
+
+	return &piece, nil
+}
+
+func convertStringToMochigoma(mochigomaString string, game *types.Game) error {
+	counts := strings.Split(mochigomaString, "/")
+	if len(counts) != types.MochigomaSize && len(counts) != types.MochigomaSize+types.HandSize {
+		return fmt.Errorf("Mochigoma must have %d or %d counts, got %d", types.MochigomaSize, types.MochigomaSize+types.HandSize, len(counts))
+	}
+
+	for i, countString := range counts {
+		count, err := getCountFromString(countString)
+		if err != nil {
+			return fmt.Errorf("Invalid mochigoma count %d: %v", i+1, err)
+		}
+		if i < types.MochigomaSize {
+			game.Mochigoma[i] = count
+		} else {
+			game.Hand[i-types.MochigomaSize] = count
+		}
+	}
+
+	return nil
+}
+
+func getTurnFromString(turnString string) (int, error) {
+	switch turnString {
+	case "w":
+		return types.White, nil
+	case "b":
+		return types.Black, nil
+	}
+
+	return -1, fmt.Errorf("Turn must be w or b, got %q", turnString)
+}
+
+func getOptionalPositionFromString(positionString string, game types.Game) (*types.Vec2, error) {
+	if positionString == "-" {
+		return nil, nil
+	}
+
+	i := 0
+	for i < len(positionString) && utils.IsLower(positionString[i]) {
+		i++
+	}
+	if i == 0 || i == len(positionString) {
+		return nil, fmt.Errorf("Position %q must be letters followed by a number", positionString)
+	}
+	for j := i; j < len(positionString); j++ {
+		if !utils.IsDigit(positionString[j]) {
+			return nil, fmt.Errorf("Position %q must be letters followed by a number", positionString)
+		}
+	}
+
+	pos, err := convertStringToPosition(positionString, game.Board.Height)
+	if err != nil {
+		return nil, err
+	}
+
+	if !checkPositionInbounds(pos, game) {
+		return nil, fmt.Errorf("Position %q is off the board", positionString)
+	}
+
+	return &pos, nil
+}
+
+func getCountFromString(countString string) (int, error) {
+	count, err := strconv.Atoi(countString)
+	if err != nil {
+		return 0, fmt.Errorf("%q is not a number", countString)
+	}
+	if count < 0 {
+		return 0, fmt.Errorf("%q cannot be negative", countString)
+	}
+
+	return count, nil
+}
+
+func convertStringToTime(timeString string, game *types.Game) error {
+	times := strings.Split(timeString, "/")
+	if len(times) != 2 {
+		return fmt.Errorf("Time must have 2 values, got %d", len(times))
+	}
+
+	for i, t := range times {
+		timeInt, err := strconv.ParseInt(t, 10, 64)
+		if err != nil {
+			return fmt.Errorf("Invalid time %q", t)
+		}
+		game.Time[i] = timeInt
+	}
+
+	return nil
+}
@@ -79,24 +79,12 @@ func convertStringToPosition(move string, boardHeight int) (types.Vec2, error) {
 }
 
 func checkDropPiece(move string) *int {
-	var result *int
-
-	if len(move) < 2 {
-		return nil
-	}
-
-	if move[1] != '*' {
-		return nil
-	}
-
-	komaChar := move[0]
-	koma, ok := types.ShogiDropCharToMochiPiece[komaChar]
+	drop, ok := getDropFromString(move)
 	if !ok {
 		return nil
 	}
 
-	result = &koma
-	return result
+	return &drop
 }
 
 func checkPromotePiece(move string) *int {
@@ -149,13 +137,10 @@ func convertStartMoveToString(move types.Move, game types.Game) (string, error)
 	}
 
 	if move.Drop != nil {
-		dropPiece := *move.Drop
-		pieceChar, ok := types.ShogiMochiPieceToChar[dropPiece]
-		if !ok {
-			return "", fmt.Errorf("Invalid Drop Piece")
+		result, err = getDropString(*move.Drop)
+		if err != nil {
+			return "", err
 		}
-
-		result = string(pieceChar) + "*"
 	}
 
 	return result, nil
@@ -0,0 +1,127 @@
+package engine
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/KainoaGardner/csc/internal/types"
+)
+
+func TestCrazyhouseCapture(t *testing.T) {
+	fen := "4ck*3/8/8/3cq*4/4CP-3/8/8/4CK*3 0/0/0/0/0/0/0/0/0/0/0/0/0/0 w - - 0 0 600000/600000"
+
+	game := loadTestGame(t, fen)
+	if err := playMove(game, "e4,d5"); err != nil {
+		t.Fatal(err)
+	}
+	if !checkHandEmpty(*game) {
+		t.Errorf("captures should not go to the hand without crazyhouse, got %v", game.Hand)
+	}
+
+	game = loadTestRulesGame(t, fen, func(rules *types.RuleSet) { rules.Crazyhouse = true })
+	if err := playMove(game, "e4,d5"); err != nil {
+		t.Fatal(err)
+	}
+	if game.Hand[types.HandQueen] != 1 {
+		t.Errorf("white should hold the captured queen, got %v", game.Hand)
+	}
+
+	if err := playMove(game, "e8,d8"); err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Contains(legalMoveStrings(t, *game), "CQ*,a4") {
+		t.Errorf("queen drop should be legal")
+	}
+	if err := playMove(game, "CQ*,a4"); err != nil {
+		t.Fatal(err)
+	}
+	piece := game.Board.Board[4][0]
+	if piece == nil || piece.Type != types.Queen || piece.Owner != types.White || !checkHandEmpty(*game) {
+		t.Errorf("queen should leave the hand for a4, got %+v and hand %v", piece, game.Hand)
+	}
+}
+
+func TestCrazyhouseDropRows(t *testing.T) {
+	fen := "4ck*3/8/8/8/8/8/8/4CK*3 0/0/0/0/0/0/0/0/0/0/0/0/0/0/1/0/0/0/0/1/0/0/0/0/0/0 w - - 0 0 600000/600000"
+	game := loadTestRulesGame(t, fen, func(rules *types.RuleSet) { rules.Crazyhouse = true })
+
+	moves := legalMoveStrings(t, *game)
+	cases := map[string]bool{
+		"CP*,a2": true,
+		"CP*,a7": true,
+		"CP*,a1": false,
+		"CP*,a8": false,
+		"KC*,a1": true,
+		"KC*,a8": false,
+	}
+	for moveString, legal := range cases {
+		if slices.Contains(moves, moveString) != legal {
+			t.Errorf("%s legal = %v, expected %v", moveString, !legal, legal)
+		}
+	}
+
+	for _, moveString := range []string{"CK*,a4", "KK*,a4", "CR*,a4"} {
+		gameCopy := copyGame(*game)
+		if err := playMove(gameCopy, moveString); err == nil {
+			t.Errorf("%s should not be playable", moveString)
+		}
+	}
+}
+
+func TestCrazyhouseFEN(t *testing.T) {
+	fen := "4ck*3/8/8/8/8/8/8/4CK*3 0/0/0/0/0/0/0/0/0/0/0/0/0/0/1/0/0/0/0/1/0/0/0/2/0/0 w - - 0 0 600000/600000"
+	game := loadTestGame(t, fen)
+
+	result, err := ConvertBoardToString(*game)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result != fen {
+		t.Errorf("got %q, expected %q", result, fen)
+	}
+
+	game.Hand = [types.HandSize]int{}
+	result, err = ConvertBoardToString(*game)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Fields(result)[1] != "0/0/0/0/0/0/0/0/0/0/0/0/0/0" {
+		t.Errorf("empty hands should keep the plain mochigoma field, got %q", result)
+	}
+}
+
+func TestCrazyhouseGameLog(t *testing.T) {
+	startFEN := "4ck*3/8/8/3cn*4/4CP-3/8/8/4CK*3 0/0/0/0/0/0/0/0/0/0/0/0/0/0 w - - 0 0 600000/600000"
+
+	game := loadTestRulesGame(t, startFEN, func(rules *types.RuleSet) { rules.Crazyhouse = true })
+	game.Board.PlaceLine = 4
+	gameLog := SetupGameLog(*game, startFEN)
+	for _, moveString := range []string{"e4,d5", "e8,d8", "CN*,f3"} {
+		if err := playMove(game, moveString); err != nil {
+			t.Fatal(err)
+		}
+		fen, err := ConvertBoardToString(*game)
+		if err != nil {
+			t.Fatal(err)
+		}
+		gameLog.Moves = append(gameLog.Moves, moveString)
+		gameLog.BoardStates = append(gameLog.BoardStates, fen)
+	}
+
+	record, err := ExportGameLog(*gameLog, "alice", "bob")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(record, "CN*f3") || !strings.Contains(record, `[EnabledRules "Crazyhouse"]`) {
+		t.Errorf("export should show the hand drop and the rule:\n%s", record)
+	}
+
+	imported, err := ImportGameLog(record)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(imported.Moves, gameLog.Moves) {
+		t.Errorf("got moves %v, expected %v", imported.Moves, gameLog.Moves)
+	}
+}
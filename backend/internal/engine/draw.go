@@ -1,32 +1,41 @@
 package engine
 
 import (
+	"fmt"
 	"github.com/KainoaGardner/csc/internal/types"
 )
 
-func GetDraw(game *types.Game) (bool, string, error) {
+// the winner is a tie unless perpetual check loses the game
+func GetDraw(game *types.Game) (bool, int, string, error) {
 	if checkStalemate(*game) {
-		return true, "Stalemate", nil
+		return true, types.Tie, "Stalemate", nil
 	}
 
 	boardString, err := ConvertBoardToStringPositionKey(*game)
 	if err != nil {
-		return false, "", err
+		return false, types.Tie, "", err
 	}
 	updateMoveHistory(boardString, game)
-	if checkThreefoldRepetition(boardString, *game) {
-		return true, "Repitition", nil
+	rules := GetRules(*game)
+	if rules.Repetition {
+		result, winner, reason, err := checkRepetition(boardString, *game)
+		if err != nil {
+			return false, types.Tie, "", err
+		}
+		if result {
+			return true, winner, reason, nil
+		}
 	}
 
-	if checkFiftyMoveRule(*game) {
-		return true, "Fifty Move Rule", nil
+	if rules.FiftyMove && checkFiftyMoveRule(*game) {
+		return true, types.Tie, "Fifty Move Rule", nil
 	}
 
-	if checkInsufficientMaterial(*game) {
-		return true, "Insufficient Material", nil
+	if rules.InsufficientMaterial && checkInsufficientMaterial(*game) {
+		return true, types.Tie, "Insufficient Material", nil
 	}
 
-	return false, "", nil
+	return false, types.Tie, "", nil
 }
 
 func checkStalemate(game types.Game) bool {
@@ -48,21 +57,128 @@ func checkStalemate(game types.Game) bool {
 }
 
 func updateMoveHistory(boardString string, game *types.Game) {
-	_, ok := game.PositionHistory[boardString]
+	record, ok := game.PositionHistory[boardString]
 	if !ok {
-		game.PositionHistory[boardString] = 0
+		//the fen of this position is stored before the next move
+		record.First = len(game.StateHistory)
+	}
+	record.Count++
+	game.PositionHistory[boardString] = record
+}
+
+func removeMoveHistory(boardString string, game *types.Game) {
+	record, ok := game.PositionHistory[boardString]
+	if !ok {
+		return
+	}
+
+	if record.Count <= 1 {
+		delete(game.PositionHistory, boardString)
+	} else {
+		record.Count--
+		game.PositionHistory[boardString] = record
+	}
+}
+
+func checkRepetition(boardString string, game types.Game) (bool, int, string, error) {
+	record := game.PositionHistory[boardString]
+	if !checkSennichite(game) {
+		return record.Count >= 3, types.Tie, "Repitition", nil
+	}
+
+	if record.Count < 4 {
+		return false, types.Tie, "", nil
+	}
+
+	checked, err := getPerpetualCheckTurn(record.First, game)
+	if err != nil {
+		return false, types.Tie, "", err
+	}
+	if checked != nil {
+		return true, *checked, "Perpetual Check", nil
+	}
+
+	return true, types.Tie, "Sennichite", nil
+}
+
+// side that was in check every time it was to move since the position first came up
+// the other side gave every check so the checked side wins
+func getPerpetualCheckTurn(first int, game types.Game) (*int, error) {
+	var positions []types.Game
+	for _, fen := range game.StateHistory[min(first, len(game.StateHistory)):] {
+		state, err := ConvertStringToBoard(fen)
+		if err != nil {
+			return nil, err
+		}
+		state.Rules = game.Rules
+		positions = append(positions, *state)
+	}
+	positions = append(positions, game)
+
+	var toMove [2]bool
+	allChecked := [2]bool{true, true}
+	for _, position := range positions {
+		if position.CheckerJump != nil { //still the same move
+			continue
+		}
+
+		toMove[position.Turn] = true
+		if !GetInCheck(position) {
+			allChecked[position.Turn] = false
+		}
 	}
-	game.PositionHistory[boardString]++
+
+	whiteChecked := toMove[types.White] && allChecked[types.White]
+	blackChecked := toMove[types.Black] && allChecked[types.Black]
+	if whiteChecked == blackChecked {
+		return nil, nil
+	}
+
+	result := types.Black
+	if whiteChecked {
+		result = types.White
+	}
+	return &result, nil
 }
 
-func checkThreefoldRepetition(boardString string, game types.Game) bool {
-	count, ok := game.PositionHistory[boardString]
-	if ok && count >= 3 {
+func checkSennichite(game types.Game) bool {
+	rule := GetRules(game).RepetitionRule
+	if rule == types.ChessRepetition {
+		return false
+	} else if rule == types.SennichiteRepetition {
 		return true
 	}
+
+	return checkShogiPieces(game)
+}
+
+func checkShogiPieces(game types.Game) bool {
+	for i := 0; i < types.MochigomaSize; i++ {
+		if game.Mochigoma[i] > 0 {
+			return true
+		}
+	}
+
+	for i := 0; i < game.Board.Height; i++ {
+		for j := 0; j < game.Board.Width; j++ {
+			piece := game.Board.Board[i][j]
+			if piece != nil && piece.Type >= types.Fu && piece.Type <= types.Ryuu {
+				return true
+			}
+		}
+	}
+
 	return false
 }
 
+func checkRepetitionRule(rule int) error {
+	if rule < types.AutoRepetition || rule > types.SennichiteRepetition {
+		return fmt.Errorf("Invalid repetition rule")
+	}
+
+	return nil
+}
+
 func checkFiftyMoveRule(game types.Game) bool {
 	if game.HalfMoveCount >= 100 {
 		return true
@@ -76,6 +192,9 @@ func checkInsufficientMaterial(game types.Game) bool {
 			return false
 		}
 	}
+	if !checkHandEmpty(game) {
+		return false
+	}
 
 	pieceCounts := [2]map[int]int{
 		make(map[int]int),
@@ -17,9 +17,14 @@ func SetupNewGame(gameConfig types.PostGame, userID string) (*types.Game, error)
 	game.Time = gameConfig.StartTime
 	game.Time[0] *= 1000
 	game.Time[1] *= 1000
+	game.TimeControl = setupTimeControl(gameConfig.TimeControl)
+	game.Periods = [2]int{game.TimeControl.Periods, game.TimeControl.Periods}
 	game.Money = gameConfig.Money
+	game.StartMoney = gameConfig.Money
+	game.Prices = setupPrices(gameConfig)
+	game.Rules = setupRules(gameConfig)
 
-	game.PositionHistory = map[string]int{}
+	game.PositionHistory = map[string]types.PositionRecord{}
 
 	game.Board.Width = gameConfig.Width
 	game.Board.Height = gameConfig.Height
@@ -33,6 +38,13 @@ func SetupNewGame(gameConfig types.PostGame, userID string) (*types.Game, error)
 	game.State = 0
 	game.Public = gameConfig.Public
 
+	//the computer always plays black and the creator joins as white
+	game.BotLevel = gameConfig.BotLevel
+	if game.BotLevel != types.NoBot {
+		game.BlackID = types.BotID
+		game.Public = false
+	}
+
 	return &game, nil
 }
 
@@ -65,6 +77,31 @@ func checkSetupConfig(gameConfig types.PostGame) error {
 		return fmt.Errorf("PlaceLine must be within the board")
 	}
 
+	err := checkTimeControlConfig(gameConfig.TimeControl)
+	if err != nil {
+		return err
+	}
+
+	err = checkBotLevel(gameConfig.BotLevel)
+	if err != nil {
+		return err
+	}
+
+	err = checkPricesConfig(gameConfig)
+	if err != nil {
+		return err
+	}
+
+	rules := setupRules(gameConfig)
+	err = checkForcedCaptureRule(rules.ForcedCapture)
+	if err != nil {
+		return err
+	}
+	err = checkRepetitionRule(rules.RepetitionRule)
+	if err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -94,9 +131,14 @@ func UpdateStartGame(game *types.Game, userID string) error {
 		return fmt.Errorf("Cant join your own game")
 	}
 
-	game.BlackID = userID
+	if game.BlackID == types.BotID {
+		game.WhiteID = userID
+	} else {
+		game.BlackID = userID
+	}
 	game.State = types.PlaceState
-	return nil
+
+	return setupBotPlacement(game)
 }
 
 func SetupJoinGame(game *types.Game, userID string) error {
@@ -122,7 +164,7 @@ func SetupJoinGame(game *types.Game, userID string) error {
 		game.State = types.PlaceState
 	}
 
-	return nil
+	return setupBotPlacement(game)
 }
 
 func GetTurnFromID(game types.Game, userID string) (int, error) {
@@ -136,15 +178,35 @@ func GetTurnFromID(game types.Game, userID string) (int, error) {
 	return -1, fmt.Errorf("Player not in game")
 }
 
-func SetupResignGame(game *types.Game, turn int) {
-	var otherTurn int
-	if turn == 0 {
-		otherTurn = 1
-	} else {
-		otherTurn = 0
+// private games can only be watched by their players
+func CheckCanSpectate(game types.Game, userID string) error {
+	if game.Public {
+		return nil
 	}
 
+	if userID == "" {
+		return fmt.Errorf("Game is private")
+	}
+	_, err := GetTurnFromID(game, userID)
+	if err != nil {
+		return fmt.Errorf("Game is private")
+	}
+
+	return nil
+}
+
+func SetupResignGame(game *types.Game, turn int) {
+	setupForfeitGame(game, turn, "Resignation")
+}
+
+func SetupAbandonGame(game *types.Game, turn int) {
+	setupForfeitGame(game, turn, "Abandonment")
+}
+
+func setupForfeitGame(game *types.Game, turn int, reason string) {
+	otherTurn := getOtherTurn(turn)
+
 	game.Winner = &otherTurn
-	game.Reason = "Resignation"
+	game.Reason = reason
 	game.State = types.OverState
 }
@@ -146,3 +146,43 @@ func convertStringToMoveTest(input string, game types.Game) error {
 
 	return nil
 }
+
+func Perft(game types.Game, depth int) (int, error) {
+	if depth <= 0 {
+		return 1, nil
+	}
+
+	moves := LegalMoves(game)
+	if depth == 1 {
+		return len(moves), nil
+	}
+
+	result := 0
+	for _, move := range moves {
+		gameCopy := copyGame(game)
+		gameCopy.PositionHistory = copyPositionHistory(game.PositionHistory)
+
+		err := applyMove(move, gameCopy)
+		if err != nil {
+			moveString, _ := ConvertMoveToString(move, game)
+			return result, fmt.Errorf("Could not apply legal move %s: %v", moveString, err)
+		}
+
+		count, err := Perft(*gameCopy, depth-1)
+		if err != nil {
+			return result, err
+		}
+		result += count
+	}
+
+	return result, nil
+}
+
+func copyPositionHistory(positionHistory map[string]types.PositionRecord) map[string]types.PositionRecord {
+	result := make(map[string]types.PositionRecord, len(positionHistory))
+	for key, record := range positionHistory {
+		result[key] = record
+	}
+
+	return result
+}
@@ -0,0 +1,285 @@
+package engine
+
+import (
+	"fmt"
+	"github.com/KainoaGardner/csc/internal/types"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// exported records look like
+//
+//	[White "alice"]
+//	[FEN "..."]
+//
+//	1. e2-e4 {[%clk 0:09:58]} e7-e5 {[%clk 0:09:57]} 2. d1-h5 ... 1-0
+//
+// moves use - or x between squares, P*e5 for drops, =Q or =+ for promotions
+// and end with + for check or # for checkmate
+
+const exportLineLength = 80
+
+func getResultString(winner *int) string {
+	if winner == nil {
+		return "*"
+	}
+
+	switch *winner {
+	case types.White:
+		return "1-0"
+	case types.Black:
+		return "0-1"
+	default:
+		return "1/2-1/2"
+	}
+}
+
+func getTimeControlString(timeControl types.TimeControl) string {
+	switch timeControl.Type {
+	case types.FischerIncrement:
+		return fmt.Sprintf("Fischer %d", timeControl.Increment/1000)
+	case types.BronsteinDelay:
+		return fmt.Sprintf("Bronstein %d", timeControl.Increment/1000)
+	case types.SimpleDelay:
+		return fmt.Sprintf("Simple delay %d", timeControl.Increment/1000)
+	case types.Byoyomi:
+		return fmt.Sprintf("Byoyomi %dx%d", timeControl.Periods, timeControl.PeriodTime/1000)
+	default:
+		return "Sudden death"
+	}
+}
+
+// h:mm:ss with milliseconds only when there are some
+func getClockString(ms int64) string {
+	ms = max(ms, 0)
+	seconds := ms / 1000
+	result := fmt.Sprintf("%d:%02d:%02d", seconds/3600, seconds/60%60, seconds%60)
+	if ms%1000 != 0 {
+		result += fmt.Sprintf(".%03d", ms%1000)
+	}
+	return result
+}
+
+// time field of a fen, or false if it has none
+func getFENTime(fen string) ([2]int64, bool) {
+	var result [2]int64
+
+	fields := strings.Fields(fen)
+	if len(fields) != 8 {
+		return result, false
+	}
+
+	game := types.Game{}
+	err := convertStringToTime(fields[7], &game)
+	if err != nil {
+		return result, false
+	}
+
+	return game.Time, true
+}
+
+func countPieces(game types.Game, owner int) int {
+	result := 0
+	for _, row := range game.Board.Board {
+		for _, piece := range row {
+			if piece != nil && piece.Owner == owner {
+				result++
+			}
+		}
+	}
+
+	return result
+}
+
+// notation for a move already played on after, before is the position it was played from
+func getMoveNotation(move types.Move, before types.Game, after types.Game) (string, error) {
+	result := ""
+
+	end, err := convertPositionToString(move.End, before)
+	if err != nil {
+		return "", err
+	}
+
+	if move.Drop != nil {
+		dropString, err := getDropString(*move.Drop)
+		if err != nil {
+			return "", err
+		}
+		result = dropString + end
+	} else {
+		start, err := convertPositionToString(move.Start, before)
+		if err != nil {
+			return "", err
+		}
+
+		separator := "-"
+		enemy := getOtherTurn(before.Turn)
+		if countPieces(after, enemy) < countPieces(before, enemy) {
+			separator = "x"
+		}
+		result = start + separator + end
+	}
+
+	if move.Promote != nil {
+		if *move.Promote == 0 {
+			result += "=+"
+		} else {
+			promotePiece, ok := types.ChessPromotePieceToChar[*move.Promote]
+			if !ok {
+				return "", fmt.Errorf("Invalid Promote Piece")
+			}
+			result += "=" + string(promotePiece)
+		}
+	}
+
+	if after.State == types.OverState && after.Reason == "Checkmate" {
+		result += "#"
+	} else if after.State == types.MoveState && GetInCheck(after) {
+		result += "+"
+	}
+
+	return result, nil
+}
+
+func setupReplayGame(startFEN string, rules *types.RuleSet) (*types.Game, error) {
+	if startFEN == "" {
+		return nil, fmt.Errorf("Game log has no starting position")
+	}
+
+	game, err := ConvertStringToBoard(startFEN)
+	if err != nil {
+		return nil, err
+	}
+	game.Rules = rules
+
+	return game, nil
+}
+
+// replays never run out of time, clocks come from the record instead
+func replayMove(moveString string, game *types.Game) (types.Move, *types.Game, error) {
+	move, err := ConvertStringToMove(moveString, *game)
+	if err != nil {
+		return move, nil, err
+	}
+
+	before := copyGame(*game)
+	game.LastMoveTime = time.Now().UTC()
+	err = MovePiece(move, game)
+	if err != nil {
+		return move, nil, err
+	}
+
+	return move, before, nil
+}
+
+// adds tokens to lines without going over the line length
+type lineWriter struct {
+	lines []string
+	line  string
+}
+
+func (l *lineWriter) write(token string) {
+	if l.line != "" && len(l.line)+1+len(token) > exportLineLength {
+		l.lines = append(l.lines, l.line)
+		l.line = ""
+	}
+
+	if l.line != "" {
+		l.line += " "
+	}
+	l.line += token
+}
+
+func (l *lineWriter) String() string {
+	if l.line != "" {
+		l.lines = append(l.lines, l.line)
+		l.line = ""
+	}
+	return strings.Join(l.lines, "\n")
+}
+
+func ExportGameLog(gameLog types.GameLog, whiteName string, blackName string) (string, error) {
+	game, err := setupReplayGame(gameLog.StartFEN, gameLog.Rules)
+	if err != nil {
+		return "", err
+	}
+
+	var sb strings.Builder
+	writeTag := func(key string, value string) {
+		value = strings.ReplaceAll(value, `"`, `'`)
+		fmt.Fprintf(&sb, "[%s \"%s\"]\n", key, value)
+	}
+
+	result := getResultString(gameLog.Winner)
+
+	writeTag("White", whiteName)
+	writeTag("Black", blackName)
+	writeTag("WhiteID", gameLog.WhiteID)
+	writeTag("BlackID", gameLog.BlackID)
+	writeTag("Date", gameLog.Date.UTC().Format("2006.01.02"))
+	writeTag("BoardSize", fmt.Sprintf("%dx%d", gameLog.BoardWidth, gameLog.BoardHeight))
+	writeTag("PlaceLine", strconv.Itoa(gameLog.BoardPlaceLine))
+	writeTag("Money", fmt.Sprintf("%d/%d", gameLog.Money[types.White], gameLog.Money[types.Black]))
+	writeTag("Time", fmt.Sprintf("%d/%d", game.Time[types.White]/1000, game.Time[types.Black]/1000))
+	writeTag("TimeControl", getTimeControlString(gameLog.TimeControl))
+	disabledRules := getChangedRulesString(GetRules(*game), false)
+	if disabledRules != "" {
+		writeTag("DisabledRules", disabledRules)
+	}
+	enabledRules := getChangedRulesString(GetRules(*game), true)
+	if enabledRules != "" {
+		writeTag("EnabledRules", enabledRules)
+	}
+	forcedCapture, ok := forcedCaptureNames[GetRules(*game).ForcedCapture]
+	if ok {
+		writeTag("ForcedCapture", forcedCapture)
+	}
+	repetitionRule, ok := repetitionRuleNames[GetRules(*game).RepetitionRule]
+	if ok {
+		writeTag("RepetitionRule", repetitionRule)
+	}
+	writeTag("Result", result)
+	writeTag("Reason", gameLog.Reason)
+	writeTag("FEN", gameLog.StartFEN)
+	sb.WriteString("\n")
+
+	var lines lineWriter
+	lastTurn := -1
+	for i, moveString := range gameLog.Moves {
+		turn := game.Turn
+		moveNumber := game.MoveCount + 1
+
+		move, before, err := replayMove(moveString, game)
+		if err != nil {
+			return "", fmt.Errorf("Move %d %s: %v", i+1, moveString, err)
+		}
+
+		//a checkers jump chain stays under one number
+		if turn != lastTurn && turn == types.White {
+			lines.write(fmt.Sprintf("%d.", moveNumber))
+		} else if turn != lastTurn && lastTurn == -1 {
+			lines.write(fmt.Sprintf("%d...", moveNumber))
+		}
+		lastTurn = turn
+
+		notation, err := getMoveNotation(move, *before, *game)
+		if err != nil {
+			return "", err
+		}
+		lines.write(notation)
+
+		if i < len(gameLog.BoardStates) {
+			clocks, ok := getFENTime(gameLog.BoardStates[i])
+			if ok {
+				lines.write(fmt.Sprintf("{[%%clk %s]}", getClockString(clocks[turn])))
+			}
+		}
+	}
+	lines.write(result)
+
+	sb.WriteString(lines.String())
+	sb.WriteString("\n")
+
+	return sb.String(), nil
+}
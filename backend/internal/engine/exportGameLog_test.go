@@ -0,0 +1,105 @@
+package engine
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/KainoaGardner/csc/internal/types"
+)
+
+func setupTestGameLog(t *testing.T, startFEN string, moves []string) types.GameLog {
+	t.Helper()
+
+	game := loadTestGame(t, startFEN)
+	game.Board.PlaceLine = 4
+	gameLog := SetupGameLog(*game, startFEN)
+	gameLog.Money = [2]int{500, 400}
+
+	for _, moveString := range moves {
+		if err := playMove(game, moveString); err != nil {
+			t.Fatalf("%s: %v", moveString, err)
+		}
+
+		fen, err := ConvertBoardToString(*game)
+		if err != nil {
+			t.Fatal(err)
+		}
+		gameLog.Moves = append(gameLog.Moves, moveString)
+		gameLog.BoardStates = append(gameLog.BoardStates, fen)
+	}
+
+	return *gameLog
+}
+
+func TestExportImportGameLog(t *testing.T) {
+	startFEN := "4ck*3/3cp*4/8/8/8/8/4CP*3/3CQ*CK*3 0/0/0/0/0/0/0/0/0/0/0/0/0/0 w - - 0 0 600000/598500"
+	moves := []string{"e2,e4", "d7,d5", "e4,d5", "e8,d7", "d1,g4", "d7,d6"}
+
+	gameLog := setupTestGameLog(t, startFEN, moves)
+	resign := types.White
+	gameLog.Winner = &resign
+	gameLog.Reason = "Resignation"
+
+	record, err := ExportGameLog(gameLog, "alice", "bob")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, expected := range []string{
+		`[White "alice"]`,
+		`[Money "500/400"]`,
+		`[Result "1-0"]`,
+		`[Reason "Resignation"]`,
+		"1. e2-e4 {[%clk 0:10:00]} d7-d5 {[%clk 0:09:58.500]} 2. e4xd5",
+		"3. d1-g4+",
+		"1-0\n",
+	} {
+		if !strings.Contains(record, expected) {
+			t.Errorf("export is missing %q:\n%s", expected, record)
+		}
+	}
+
+	imported, err := ImportGameLog(record)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !slices.Equal(imported.Moves, gameLog.Moves) {
+		t.Errorf("got moves %v, expected %v", imported.Moves, gameLog.Moves)
+	}
+	if !slices.Equal(imported.BoardStates, gameLog.BoardStates) {
+		t.Errorf("got board states %v, expected %v", imported.BoardStates, gameLog.BoardStates)
+	}
+	if imported.Winner == nil || *imported.Winner != types.White || imported.Reason != "Resignation" {
+		t.Errorf("result was not kept, got winner %v reason %s", imported.Winner, imported.Reason)
+	}
+	if imported.Money != gameLog.Money || imported.StartFEN != startFEN {
+		t.Errorf("headers were not kept, got money %v fen %s", imported.Money, imported.StartFEN)
+	}
+}
+
+func TestImportGameLogErrors(t *testing.T) {
+	header := "[FEN \"4ck*3/3cp*4/8/8/8/8/4CP*3/3CQ*CK*3 0/0/0/0/0/0/0/0/0/0/0/0/0/0 w - - 0 0 600000/600000\"]\n\n"
+
+	cases := []struct {
+		name   string
+		record string
+	}{
+		{"illegal move", header + "1. e2-e5 *"},
+		{"missing capture marker", header + "1. e2-e4 d7-d5 2. e4-d5 *"},
+		{"wrong check marker", header + "1. e2-e4+ *"},
+		{"result does not match tag", "[Result \"1-0\"]\n" + header + "1. e2-e4 0-1"},
+		{"moves after the result", header + "1. e2-e4 * d7-d5"},
+		{"no starting position", "[White \"alice\"]\n\n1. e2-e4 *"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			_, err := ImportGameLog(c.record)
+			if err == nil {
+				t.Errorf("import should fail")
+			}
+		})
+	}
+}
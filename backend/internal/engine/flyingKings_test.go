@@ -0,0 +1,108 @@
+package engine
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/KainoaGardner/csc/internal/types"
+)
+
+func loadTestRulesGame(t *testing.T, fen string, setRules func(rules *types.RuleSet)) *types.Game {
+	t.Helper()
+
+	rules := types.DefaultRuleSet
+	setRules(&rules)
+
+	game := loadTestGame(t, fen)
+	game.Rules = &rules
+
+	return game
+}
+
+func TestFlyingKingMoves(t *testing.T) {
+	fen := "4ck*3/8/8/8/3kc*4/8/8/KK*3CK*3 0/0/0/0/0/0/0/0/0/0/0/0/0/0 w - - 0 0 600000/600000"
+
+	game := loadTestGame(t, fen)
+	moves := legalMoveStrings(t, *game)
+	if !slices.Contains(moves, "a1,b2") || slices.Contains(moves, "a1,c3") || slices.Contains(moves, "a1,e5") {
+		t.Errorf("checker king should only step without flying kings, got %v", moves)
+	}
+
+	game = loadTestRulesGame(t, fen, func(rules *types.RuleSet) { rules.FlyingKings = true })
+	moves = legalMoveStrings(t, *game)
+	for _, moveString := range []string{"a1,b2", "a1,c3", "a1,e5", "a1,f6", "a1,h8"} {
+		if !slices.Contains(moves, moveString) {
+			t.Errorf("flying king move %s should be legal, got %v", moveString, moves)
+		}
+	}
+	if slices.Contains(moves, "a1,d4") {
+		t.Errorf("flying king cannot land on a piece")
+	}
+
+	if err := playMove(game, "a1,g7"); err != nil {
+		t.Fatal(err)
+	}
+	if game.Board.Board[4][3] != nil {
+		t.Errorf("captured checker on d4 should be removed")
+	}
+	if game.Turn != types.Black || game.HalfMoveCount != 0 {
+		t.Errorf("capture should pass the turn and reset the half move count")
+	}
+}
+
+func TestFlyingKingMultiCapture(t *testing.T) {
+	game := loadTestRulesGame(t, "4ck*3/8/8/8/3kc*4/6kc*1/8/KK*3CK*3 0/0/0/0/0/0/0/0/0/0/0/0/0/0 w - - 0 0 600000/600000", func(rules *types.RuleSet) { rules.FlyingKings = true })
+
+	if err := playMove(game, "a1,e5"); err != nil {
+		t.Fatal(err)
+	}
+	if game.Turn != types.White || game.CheckerJump == nil {
+		t.Fatalf("white should have to continue capturing")
+	}
+
+	moves := legalMoveStrings(t, *game)
+	if !slices.Equal(moves, []string{"e5,h2"}) {
+		t.Errorf("only the long capture over g3 should be legal, got %v", moves)
+	}
+
+	if err := playMove(game, "e5,h2"); err != nil {
+		t.Fatal(err)
+	}
+	if game.Turn != types.Black || game.Board.Board[5][6] != nil {
+		t.Errorf("both black checkers should be captured")
+	}
+}
+
+func TestBackwardCaptures(t *testing.T) {
+	fen := "4ck*3/8/8/8/8/2KC*5/1kc*6/4CK*3 0/0/0/0/0/0/0/0/0/0/0/0/0/0 w - - 0 0 600000/600000"
+
+	game := loadTestGame(t, fen)
+	if slices.Contains(legalMoveStrings(t, *game), "c3,a1") {
+		t.Errorf("checker men should not capture backward by default")
+	}
+
+	game = loadTestRulesGame(t, fen, func(rules *types.RuleSet) { rules.BackwardCaptures = true })
+	moves := legalMoveStrings(t, *game)
+	if !slices.Contains(moves, "c3,a1") || slices.Contains(moves, "c3,d2") {
+		t.Errorf("expected a backward capture and no backward step, got %v", moves)
+	}
+
+	if err := playMove(game, "c3,a1"); err != nil {
+		t.Fatal(err)
+	}
+	if game.Board.Board[6][1] != nil {
+		t.Errorf("captured checker on b2 should be removed")
+	}
+}
+
+func TestCheckerCaptureUncoversKing(t *testing.T) {
+	//the queen on f6 pins e7 to the king on d8 unless e7 jumps it
+	game := loadTestGame(t, perftPositions[1].fen)
+
+	if err := playMove(game, "a1,f6"); err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Contains(legalMoveStrings(t, *game), "e7,g5") {
+		t.Errorf("e7,g5 captures the only attacker and should be legal")
+	}
+}
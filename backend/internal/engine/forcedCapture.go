@@ -0,0 +1,86 @@
+package engine
+
+import (
+	"fmt"
+	"github.com/KainoaGardner/csc/internal/types"
+)
+
+// a checker of the side to move has a jump that does not leave its king in check
+func checkCheckerCanJump(game types.Game) bool {
+	dir := getMoveDirection(game)
+
+	for i := 0; i < game.Board.Height; i++ {
+		for j := 0; j < game.Board.Width; j++ {
+			space := game.Board.Board[i][j]
+			if space == nil || space.Owner != game.Turn || !checkCheckerPiece(*space) {
+				continue
+			}
+
+			pos := types.Vec2{X: j, Y: i}
+			possibleMoves := getPieceMoves(pos, *space, game, dir)
+			filterPossibleMoves(pos, &possibleMoves, game)
+			for _, end := range possibleMoves {
+				if getCheckerTakePos(pos, end, game) != nil {
+					return true
+				}
+			}
+		}
+	}
+
+	return false
+}
+
+// quiet moves the forced capture rule takes away while a jump is available
+func checkForcedCaptureMove(move types.Move, piece types.Piece, game types.Game) bool {
+	rule := GetRules(game).ForcedCapture
+	if rule == types.NoForcedCapture || game.CheckerJump != nil {
+		return false
+	}
+
+	if checkCheckerPieceTake(move, piece, game) {
+		return false
+	}
+
+	isChecker := move.Drop == nil && checkCheckerPiece(piece)
+	return rule == types.AllForcedCapture || isChecker
+}
+
+func checkForcedCapture(move types.Move, piece types.Piece, game types.Game) error {
+	if checkForcedCaptureMove(move, piece, game) && checkCheckerCanJump(game) {
+		return fmt.Errorf("Must jump with a checker")
+	}
+
+	return nil
+}
+
+// moves are already legal so any checker jump among them means one is available
+func filterForcedCapture(moves []types.Move, game types.Game) []types.Move {
+	canJump := false
+	for _, move := range moves {
+		if move.Drop == nil && checkCheckerPieceTake(move, *game.Board.Board[move.Start.Y][move.Start.X], game) {
+			canJump = true
+			break
+		}
+	}
+	if !canJump {
+		return moves
+	}
+
+	result := []types.Move{}
+	for _, move := range moves {
+		piece, err := getPiece(move, game)
+		if err != nil || !checkForcedCaptureMove(move, *piece, game) {
+			result = append(result, move)
+		}
+	}
+
+	return result
+}
+
+func checkForcedCaptureRule(rule int) error {
+	if rule < types.NoForcedCapture || rule > types.AllForcedCapture {
+		return fmt.Errorf("Invalid forced capture rule")
+	}
+
+	return nil
+}
@@ -0,0 +1,83 @@
+package engine
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/KainoaGardner/csc/internal/types"
+)
+
+func TestForcedCapture(t *testing.T) {
+	fen := "7ck*/8/8/8/8/8/1kc*6/KC*4KC*CR*CK* 0/0/0/0/0/0/0/0/0/0/0/0/0/0 w - - 0 0 600000/600000"
+
+	cases := []struct {
+		name  string
+		rule  int
+		legal map[string]bool
+	}{
+		{"off", types.NoForcedCapture, map[string]bool{"a1,c3": true, "f1,e2": true, "g1,g5": true}},
+		{"checkers", types.CheckerForcedCapture, map[string]bool{"a1,c3": true, "f1,e2": false, "g1,g5": true}},
+		{"all", types.AllForcedCapture, map[string]bool{"a1,c3": true, "f1,e2": false, "g1,g5": false}},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			rules := types.DefaultRuleSet
+			rules.ForcedCapture = c.rule
+
+			game := loadTestGame(t, fen)
+			game.Rules = &rules
+
+			moves := legalMoveStrings(t, *game)
+			if c.rule == types.AllForcedCapture && !slices.Equal(moves, []string{"a1,c3"}) {
+				t.Errorf("only the jump should be legal, got %v", moves)
+			}
+
+			for moveString, legal := range c.legal {
+				if slices.Contains(moves, moveString) != legal {
+					t.Errorf("%s legal = %v, expected %v", moveString, !legal, legal)
+				}
+
+				gameCopy := copyGame(*game)
+				err := playMove(gameCopy, moveString)
+				if (err == nil) != legal {
+					t.Errorf("MovePiece %s got %v, expected legal = %v", moveString, err, legal)
+				}
+			}
+		})
+	}
+}
+
+func TestForcedCaptureIllegalJump(t *testing.T) {
+	//the only jump opens the a file to the rook so nothing is forced
+	game := loadTestGame(t, "cr*6ck*/8/8/8/8/1kc*6/KC*7/CK*7 0/0/0/0/0/0/0/0/0/0/0/0/0/0 w - - 0 0 600000/600000")
+	rules := types.DefaultRuleSet
+	rules.ForcedCapture = types.AllForcedCapture
+	game.Rules = &rules
+
+	moves := legalMoveStrings(t, *game)
+	if slices.Contains(moves, "a2,c4") || !slices.Contains(moves, "a1,b1") {
+		t.Errorf("expected king moves and no jump, got %v", moves)
+	}
+
+	err := playMove(game, "a1,b1")
+	if err != nil {
+		t.Error(err)
+	}
+}
+
+func TestForcedCaptureConfig(t *testing.T) {
+	forcedCapture := 5
+
+	postGame := types.PostGame{
+		Width:     8,
+		Height:    8,
+		PlaceLine: 4,
+		Money:     [2]int{100, 100},
+		Rules:     &types.PostRuleSet{ForcedCapture: &forcedCapture},
+	}
+	_, err := SetupNewGame(postGame, "white")
+	if err == nil {
+		t.Errorf("forced capture rule 5 should not exist")
+	}
+}
@@ -1,61 +1,81 @@
 package engine
 
 import (
-	"github.com/KainoaGardner/csc/internal/db"
+	"github.com/KainoaGardner/csc/internal/rating"
+	"github.com/KainoaGardner/csc/internal/store"
 	"github.com/KainoaGardner/csc/internal/types"
 	// "github.com/KainoaGardner/csc/internal/utils"
 
+	"errors"
 	"fmt"
-	"github.com/KainoaGardner/csc/internal/config"
-	"go.mongodb.org/mongo-driver/mongo"
 )
 
-func JoinGameCase(gameID string, userID string, client *mongo.Client, config config.Config) (*types.Game, error) {
-	game, err := db.FindGame(client, config.DB, gameID)
-	if err != nil {
-		return nil, err
-	}
+const maxUpdateAttempts = 5
 
-	err = SetupJoinGame(game, userID)
-	if err != nil {
-		return nil, err
-	}
+type gameUpdate func(string, types.Game) error
 
-	err = db.GameMoveUpdate(client, config.DB, gameID, *game)
-	if err != nil {
-		return nil, err
-	}
+// loads the game, applies change and writes it back with update
+// if another request wrote the game first it is reloaded and change runs again
+func updateGameCase(gameID string, gameStore store.GameStore, change func(*types.Game) error, update gameUpdate) (*types.Game, error) {
+	for attempt := 1; ; attempt++ {
+		game, err := gameStore.FindGame(gameID)
+		if err != nil {
+			return nil, err
+		}
 
-	return game, nil
-}
+		err = change(game)
+		if err != nil {
+			return nil, err
+		}
 
-func MoveCase(gameID string, userID string, postMove types.PostMove, client *mongo.Client, config config.Config) (*types.Game, string, error) {
-	game, err := db.FindGame(client, config.DB, gameID)
-	if err != nil {
-		return nil, "", err
-	}
+		err = update(gameID, *game)
+		if errors.Is(err, store.ErrVersionConflict) && attempt < maxUpdateAttempts {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
 
-	turn, err := GetTurnFromID(*game, userID)
-	if err != nil {
-		return nil, "", err
+		game.Version++
+		return game, nil
 	}
+}
 
-	err = CheckTurn(turn, game.Turn)
-	if err != nil {
-		return nil, "", err
+// a rollback rewrites the board, a request only sets the flags
+func takebackUpdate(gameStore store.GameStore, count *int) gameUpdate {
+	return func(gameID string, game types.Game) error {
+		if *count > 0 {
+			return gameStore.GameMoveUpdate(gameID, game)
+		}
+		return gameStore.GameTakebackUpdate(gameID, game)
 	}
+}
 
-	move, err := ConvertStringToMove(postMove.Move, *game)
-	if err != nil {
-		return nil, "", err
-	}
+func JoinGameCase(gameID string, userID string, store store.Store) (*types.Game, error) {
+	return updateGameCase(gameID, store, func(game *types.Game) error {
+		return SetupJoinGame(game, userID)
+	}, store.GameMoveUpdate)
+}
 
-	err = MovePiece(move, game)
-	if err != nil {
-		return nil, "", err
-	}
+func MoveCase(gameID string, userID string, postMove types.PostMove, store store.Store) (*types.Game, string, error) {
+	game, err := updateGameCase(gameID, store, func(game *types.Game) error {
+		turn, err := GetTurnFromID(*game, userID)
+		if err != nil {
+			return err
+		}
 
-	err = db.GameMoveUpdate(client, config.DB, gameID, *game)
+		err = CheckTurn(turn, game.Turn)
+		if err != nil {
+			return err
+		}
+
+		move, err := ConvertStringToMove(postMove.Move, *game)
+		if err != nil {
+			return err
+		}
+
+		return MovePiece(move, game)
+	}, store.GameMoveUpdate)
 	if err != nil {
 		return nil, "", err
 	}
@@ -65,7 +85,7 @@ func MoveCase(gameID string, userID string, postMove types.PostMove, client *mon
 		return nil, "", err
 	}
 
-	err = db.GameLogUpdate(client, config.DB, gameID, postMove.Move, fen)
+	err = store.GameLogUpdate(gameID, postMove.Move, fen)
 	if err != nil {
 		return nil, "", err
 	}
@@ -73,60 +93,63 @@ func MoveCase(gameID string, userID string, postMove types.PostMove, client *mon
 	return game, fen, nil
 }
 
-func PlaceCase(gameID string, userID string, postPlace types.PostPlace, client *mongo.Client, config config.Config) (*types.Game, types.PlaceResponse, error) {
-	var result types.PlaceResponse
+func LegalMovesCase(gameID string, userID string, store store.Store) (*types.Game, []string, error) {
+	game, err := store.FindGame(gameID)
+	if err != nil {
+		return nil, nil, err
+	}
 
-	game, err := db.FindGame(client, config.DB, gameID)
+	_, err = GetTurnFromID(*game, userID)
 	if err != nil {
-		return nil, result, err
+		return nil, nil, err
 	}
 
-	turn, err := GetTurnFromID(*game, userID)
+	moves, err := LegalMoveStrings(*game)
 	if err != nil {
-		return nil, result, err
+		return nil, nil, err
 	}
 
-	var place types.Place
-	//if place else delete placed piece
+	return game, moves, nil
+}
 
-	switch postPlace.Place {
-	case types.CreatePlaceEnum:
-		place, err = SetupPlace(postPlace, turn, *game)
-		if err != nil {
-			return nil, result, err
-		}
+func PlaceCase(gameID string, userID string, postPlace types.PostPlace, store store.Store) (*types.Game, types.PlaceResponse, error) {
+	var result types.PlaceResponse
+	var place types.Place
 
-		err = PlacePiece(place, game)
+	game, err := updateGameCase(gameID, store, func(game *types.Game) error {
+		turn, err := GetTurnFromID(*game, userID)
 		if err != nil {
-			return nil, result, err
+			return err
 		}
 
-	case types.DeletePlaceEnum:
-		place, err = SetupDeletePlace(postPlace, turn, *game)
-		if err != nil {
-			return nil, result, err
+		//if place else delete placed piece
+		switch postPlace.Place {
+		case types.CreatePlaceEnum:
+			place, err = SetupPlace(postPlace, turn, *game)
+			if err != nil {
+				return err
+			}
+
+			return PlacePiece(place, game)
+
+		case types.DeletePlaceEnum:
+			place, err = SetupDeletePlace(postPlace, turn, *game)
+			if err != nil {
+				return err
+			}
+			return PlacePieceDelete(&place, game)
+
+		case types.MovePlaceEnum:
+			place, err = SetupMovePlace(postPlace, turn, *game)
+			if err != nil {
+				return err
+			}
+			return PlacePieceMove(&place, game)
+
+		default:
+			return fmt.Errorf("Incorrect place selection")
 		}
-		err = PlacePieceDelete(&place, game)
-		if err != nil {
-			return nil, result, err
-		}
-
-	case types.MovePlaceEnum:
-		place, err = SetupMovePlace(postPlace, turn, *game)
-		if err != nil {
-			return nil, result, err
-		}
-		err = PlacePieceMove(&place, game)
-		if err != nil {
-			return nil, result, err
-		}
-
-	default:
-		return nil, result, fmt.Errorf("Incorrect place selection")
-
-	}
-
-	err = db.GamePlaceUpdate(client, config.DB, gameID, place, *game)
+	}, store.GamePlaceUpdate)
 	if err != nil {
 		return nil, result, err
 	}
@@ -158,23 +181,50 @@ func PlaceCase(gameID string, userID string, postPlace types.PostPlace, client *
 	return game, result, nil
 }
 
-func ReadyCase(gameID string, userID string, postReady types.PostReady, client *mongo.Client, config config.Config) (*types.Game, string, error) {
-	game, err := db.FindGame(client, config.DB, gameID)
-	if err != nil {
-		return nil, "", err
-	}
+func ArmyCase(gameID string, userID string, postArmy types.PostArmy, store store.Store) (*types.Game, types.ArmyResponse, error) {
+	var result types.ArmyResponse
 
-	turn, err := GetTurnFromID(*game, userID)
-	if err != nil {
-		return nil, "", err
+	if !postArmy.Place {
+		game, err := store.FindGame(gameID)
+		if err != nil {
+			return nil, result, err
+		}
+
+		turn, err := GetTurnFromID(*game, userID)
+		if err != nil {
+			return nil, result, err
+		}
+
+		result, err = ProposeArmy(postArmy.Preset, turn, *game)
+		return game, result, err
 	}
 
-	err = ReadyPlayer(postReady.Ready, turn, game)
+	//every piece goes through PlacePiece so the update is the same as a single place
+	game, err := updateGameCase(gameID, store, func(game *types.Game) error {
+		turn, err := GetTurnFromID(*game, userID)
+		if err != nil {
+			return err
+		}
+
+		result, err = PlaceArmy(postArmy.Preset, turn, game)
+		return err
+	}, store.GamePlaceUpdate)
 	if err != nil {
-		return nil, "", err
+		return nil, result, err
 	}
 
-	err = db.GameReadyUpdate(client, config.DB, gameID, *game)
+	return game, result, nil
+}
+
+func ReadyCase(gameID string, userID string, postReady types.PostReady, store store.Store) (*types.Game, string, error) {
+	game, err := updateGameCase(gameID, store, func(game *types.Game) error {
+		turn, err := GetTurnFromID(*game, userID)
+		if err != nil {
+			return err
+		}
+
+		return ReadyPlayer(postReady.Ready, turn, game)
+	}, store.GameReadyUpdate)
 	if err != nil {
 		return nil, "", err
 	}
@@ -187,47 +237,151 @@ func ReadyCase(gameID string, userID string, postReady types.PostReady, client *
 	return game, fen, nil
 }
 
-func DrawCase(gameID string, userID string, postDraw types.PostDrawRequest, client *mongo.Client, config config.Config) (*types.Game, error) {
-	game, err := db.FindGame(client, config.DB, gameID)
+func DrawCase(gameID string, userID string, postDraw types.PostDrawRequest, store store.Store) (*types.Game, error) {
+	return updateGameCase(gameID, store, func(game *types.Game) error {
+		turn, err := GetTurnFromID(*game, userID)
+		if err != nil {
+			return err
+		}
+
+		return DrawRequest(postDraw.Draw, turn, game)
+	}, store.GameDrawUpdate)
+}
+
+func TakebackCase(gameID string, userID string, postTakeback types.PostTakebackRequest, store store.Store) (*types.Game, types.TakebackResponse, error) {
+	var result types.TakebackResponse
+	var count int
+
+	game, err := updateGameCase(gameID, store, func(game *types.Game) error {
+		turn, err := GetTurnFromID(*game, userID)
+		if err != nil {
+			return err
+		}
+
+		count, err = TakebackRequest(postTakeback.Takeback, turn, game)
+		if err != nil {
+			return err
+		}
+
+		//the computer agrees to every takeback
+		botTurn, ok := GetBotTurn(*game)
+		if ok && postTakeback.Takeback && count == 0 {
+			count, err = TakebackRequest(true, botTurn, game)
+		}
+		return err
+	}, takebackUpdate(store, &count))
 	if err != nil {
-		return nil, err
+		return nil, result, err
 	}
 
-	turn, err := GetTurnFromID(*game, userID)
-	if err != nil {
-		return nil, err
+	if count > 0 {
+		err = store.GameLogTakebackUpdate(gameID, count)
+		if err != nil {
+			return nil, result, err
+		}
 	}
 
-	err = DrawRequest(postDraw.Draw, turn, game)
+	fen, err := ConvertBoardToString(*game)
 	if err != nil {
-		return nil, err
+		return nil, result, err
 	}
 
-	err = db.GameDrawUpdate(client, config.DB, gameID, *game)
-	if err != nil {
-		return nil, err
+	result = types.TakebackResponse{
+		ID:       game.ID,
+		Takeback: game.Takeback,
+		Count:    count,
+		FEN:      fen,
+		Clock:    GetClock(*game),
 	}
 
-	return game, nil
+	return game, result, nil
 }
 
-func ResignCase(gameID string, userID string, client *mongo.Client, config config.Config) (*types.Game, error) {
-	game, err := db.FindGame(client, config.DB, gameID)
+func ResignCase(gameID string, userID string, store store.Store) (*types.Game, error) {
+	return updateGameCase(gameID, store, func(game *types.Game) error {
+		turn, err := GetTurnFromID(*game, userID)
+		if err != nil {
+			return err
+		}
+
+		SetupResignGame(game, turn)
+		return nil
+	}, store.GameMoveUpdate)
+}
+
+// games abandoned before moves start have no game log so they are deleted, the bool is true when that happened
+func AbandonCase(gameID string, userID string, store store.Store) (*types.Game, bool, error) {
+	game, err := store.FindGame(gameID)
 	if err != nil {
-		return nil, err
+		return nil, false, err
 	}
 
-	turn, err := GetTurnFromID(*game, userID)
+	if game.State == types.ConnectState || game.State == types.PlaceState {
+		turn, err := GetTurnFromID(*game, userID)
+		if err != nil {
+			return nil, false, err
+		}
+
+		_, err = store.DeleteGame(gameID)
+		if err != nil {
+			return nil, false, err
+		}
+
+		SetupAbandonGame(game, turn)
+		return game, true, nil
+	}
+
+	game, err = updateGameCase(gameID, store, func(game *types.Game) error {
+		turn, err := GetTurnFromID(*game, userID)
+		if err != nil {
+			return err
+		}
+
+		err = checkGameState(types.MoveState, game.State)
+		if err != nil {
+			return err
+		}
+
+		SetupAbandonGame(game, turn)
+		return nil
+	}, store.GameMoveUpdate)
+	return game, false, err
+}
+
+func GameOverCase(game types.Game, gameID string, store store.Store) error {
+	gameLog, err := store.FindGameLogFromGameID(gameID)
 	if err != nil {
-		return nil, err
+		return err
+	}
+	SetupFinalGameLog(game, gameLog)
+
+	//games against the computer are unrated
+	if game.BotLevel != types.NoBot {
+		return store.FinishGame(gameID, *gameLog, map[string]types.UpdateUserStats{})
 	}
 
-	SetupResignGame(game, turn)
+	whiteStats, err := store.FindUserStatsFromUserID(game.WhiteID)
+	if err != nil {
+		return err
+	}
 
-	err = db.GameMoveUpdate(client, config.DB, gameID, *game)
+	blackStats, err := store.FindUserStatsFromUserID(game.BlackID)
 	if err != nil {
-		return nil, err
+		return err
+	}
+
+	winner := types.Tie
+	if game.Winner != nil {
+		winner = *game.Winner
+	}
+	whiteScore, blackScore := rating.GetScores(winner)
+
+	//both updates use the ratings from before the game
+	gameLogID := gameLog.ID.Hex()
+	statsUpdates := map[string]types.UpdateUserStats{
+		game.WhiteID: SetupUserStatsUpdate(*whiteStats, *blackStats, whiteScore, gameLogID),
+		game.BlackID: SetupUserStatsUpdate(*blackStats, *whiteStats, blackScore, gameLogID),
 	}
 
-	return game, nil
+	return store.FinishGame(gameID, *gameLog, statsUpdates)
 }
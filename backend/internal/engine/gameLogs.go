@@ -5,7 +5,7 @@ import (
 	"time"
 )
 
-func SetupGameLog(game types.Game) *types.GameLog {
+func SetupGameLog(game types.Game, startFEN string) *types.GameLog {
 	var result types.GameLog
 
 	result.GameID = game.ID
@@ -16,6 +16,15 @@ func SetupGameLog(game types.Game) *types.GameLog {
 	result.BoardWidth = game.Board.Width
 	result.BoardPlaceLine = game.Board.PlaceLine
 
+	result.StartFEN = startFEN
+	result.Money = game.StartMoney
+	result.TimeControl = game.TimeControl
+	result.Rules = game.Rules
+	result.Placements = game.Placements
+	if result.Placements == nil {
+		result.Placements = []types.PlacementRecord{}
+	}
+
 	result.Moves = []string{}
 	result.BoardStates = []string{}
 
@@ -0,0 +1,159 @@
+package engine
+
+import (
+	"testing"
+	"time"
+
+	"github.com/KainoaGardner/csc/internal/store"
+	"github.com/KainoaGardner/csc/internal/types"
+)
+
+func createTestGame(t *testing.T, gameStore store.Store, fen string) string {
+	t.Helper()
+
+	game := loadTestGame(t, fen)
+	game.WhiteID = "white"
+	game.BlackID = "black"
+	game.State = types.MoveState
+	game.LastMoveTime = time.Now().UTC()
+
+	gameID, err := gameStore.CreateGame(game)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return gameID
+}
+
+func TestMoveCase(t *testing.T) {
+	gameStore := store.NewMemoryStore()
+	gameID := createTestGame(t, gameStore, "4ck*3/4cp*3/8/8/8/8/4CP*3/4CK*3 0/0/0/0/0/0/0/0/0/0/0/0/0/0 w - - 0 0 600000/600000")
+
+	_, _, err := MoveCase(gameID, "black", types.PostMove{Move: "e7,e5"}, gameStore)
+	if err == nil {
+		t.Fatalf("black should not be able to move on white's turn")
+	}
+
+	_, fen, err := MoveCase(gameID, "white", types.PostMove{Move: "e2,e4"}, gameStore)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	game, err := gameStore.FindGame(gameID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if game.Turn != types.Black || game.Version != 1 {
+		t.Errorf("got turn %d version %d, expected black to move at version 1", game.Turn, game.Version)
+	}
+
+	storedFEN, err := ConvertBoardToString(*game)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if storedFEN != fen {
+		t.Errorf("stored game %q does not match returned fen %q", storedFEN, fen)
+	}
+}
+
+func TestUpdateGameCaseRetry(t *testing.T) {
+	gameStore := store.NewMemoryStore()
+	gameID := createTestGame(t, gameStore, "4ck*3/4cp*3/8/8/8/8/4CP*3/4CK*3 0/0/0/0/0/0/0/0/0/0/0/0/0/0 w - - 0 0 600000/600000")
+
+	attempts := 0
+	game, err := updateGameCase(gameID, gameStore, func(game *types.Game) error {
+		attempts++
+		if attempts == 1 {
+			//another request writes after this one loaded the game
+			other := *game
+			other.Draw[types.Black] = true
+			if err := gameStore.GameDrawUpdate(gameID, other); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		game.Takeback[types.White] = true
+		return nil
+	}, gameStore.GameTakebackUpdate)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if attempts != 2 {
+		t.Errorf("got %d attempts, expected a retry after the conflict", attempts)
+	}
+	if !game.Draw[types.Black] || !game.Takeback[types.White] || game.Version != 2 {
+		t.Errorf("retry should keep the other write, got draw %v takeback %v version %d", game.Draw, game.Takeback, game.Version)
+	}
+}
+
+func TestCheckCanSpectate(t *testing.T) {
+	game := types.Game{WhiteID: "white", BlackID: types.BotID}
+
+	cases := []struct {
+		name   string
+		public bool
+		userID string
+		ok     bool
+	}{
+		{"public game", true, "", true},
+		{"private game player", false, "white", true},
+		{"private game stranger", false, "someone", false},
+		{"private game no login", false, "", false},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			game.Public = c.public
+			err := CheckCanSpectate(game, c.userID)
+			if (err == nil) != c.ok {
+				t.Errorf("got %v, expected ok = %v", err, c.ok)
+			}
+		})
+	}
+}
+
+func TestAbandonCase(t *testing.T) {
+	gameStore := store.NewMemoryStore()
+	fen := "4ck*3/4cp*3/8/8/8/8/4CP*3/4CK*3 0/0/0/0/0/0/0/0/0/0/0/0/0/0 w - - 0 0 600000/600000"
+
+	placeGameID := createTestGame(t, gameStore, fen)
+	placeGame, err := gameStore.FindGame(placeGameID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	placeGame.State = types.PlaceState
+	err = gameStore.GameStateUpdate(placeGameID, *placeGame)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, _, err = AbandonCase(placeGameID, "someone", gameStore)
+	if err == nil {
+		t.Errorf("only players should be able to abandon")
+	}
+
+	game, deleted, err := AbandonCase(placeGameID, "white", gameStore)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !deleted || game.State != types.OverState || *game.Winner != types.Black || game.Reason != "Abandonment" {
+		t.Errorf("placement game should be deleted with black winning, got deleted %v %+v", deleted, game)
+	}
+	if _, err := gameStore.FindGame(placeGameID); err != store.ErrNotFound {
+		t.Errorf("abandoned placement game should be deleted, got %v", err)
+	}
+
+	moveGameID := createTestGame(t, gameStore, fen)
+	game, deleted, err = AbandonCase(moveGameID, "black", gameStore)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if deleted || game.State != types.OverState || *game.Winner != types.White {
+		t.Errorf("running game should end with white winning, got deleted %v %+v", deleted, game)
+	}
+	stored, err := gameStore.FindGame(moveGameID)
+	if err != nil || stored.State != types.OverState {
+		t.Errorf("running game should be kept for its game log, got %v", err)
+	}
+}
@@ -0,0 +1,116 @@
+package engine
+
+import (
+	"fmt"
+	"github.com/KainoaGardner/csc/internal/types"
+	"strings"
+)
+
+func getHandOffset(game types.Game) int {
+	offset := 0
+	if game.Turn == 1 {
+		offset = types.HandBlackOffset
+	}
+
+	return offset
+}
+
+// every Move.Drop value, mochigoma first then the crazyhouse hand
+func getAllDrops() []int {
+	var result []int
+	for i := 0; i < types.MochigomaBlackOffset; i++ {
+		result = append(result, i)
+	}
+	for i := 0; i < types.HandBlackOffset; i++ {
+		result = append(result, types.HandDropOffset+i)
+	}
+
+	return result
+}
+
+func getDropPieceType(drop int) (int, bool) {
+	if drop >= types.HandDropOffset {
+		pieceType, ok := types.HandPieceToDropPiece[drop-types.HandDropOffset]
+		return pieceType, ok
+	}
+
+	pieceType, ok := types.ShogiMochiPieceToDropPiece[drop]
+	return pieceType, ok
+}
+
+// pieces the side to move has in hand to drop as drop
+func getDropCount(drop int, game types.Game) int {
+	if drop >= types.HandDropOffset {
+		return game.Hand[drop-types.HandDropOffset+getHandOffset(game)]
+	}
+
+	return game.Mochigoma[drop+getMochigomaOffset(game)]
+}
+
+func removeDropPiece(drop int, game *types.Game) {
+	if drop >= types.HandDropOffset {
+		game.Hand[drop-types.HandDropOffset+getHandOffset(*game)]--
+	} else {
+		game.Mochigoma[drop+getMochigomaOffset(*game)]--
+	}
+}
+
+// captured chess and checkers pieces go to the capturer in crazyhouse games
+func updateHand(takePiece *types.Piece, game *types.Game) {
+	if takePiece == nil || takePiece.Owner == game.Turn || !GetRules(*game).Crazyhouse {
+		return
+	}
+
+	handPiece, ok := types.PieceToHandPiece[takePiece.Type]
+	if ok {
+		game.Hand[handPiece+getHandOffset(*game)]++
+	}
+}
+
+func checkHandEmpty(game types.Game) bool {
+	for _, count := range game.Hand {
+		if count > 0 {
+			return false
+		}
+	}
+
+	return true
+}
+
+// P* for mochigoma and the fen piece like CP* for the hand
+func getDropString(drop int) (string, error) {
+	if drop >= types.HandDropOffset {
+		pieceType, ok := getDropPieceType(drop)
+		if !ok {
+			return "", fmt.Errorf("Invalid Drop Piece")
+		}
+		return types.FenPieceToString[pieceType] + "*", nil
+	}
+
+	pieceChar, ok := types.ShogiMochiPieceToChar[drop]
+	if !ok {
+		return "", fmt.Errorf("Invalid Drop Piece")
+	}
+	return string(pieceChar) + "*", nil
+}
+
+func getDropFromString(dropString string) (int, bool) {
+	pieceString, ok := strings.CutSuffix(dropString, "*")
+	if !ok {
+		return 0, false
+	}
+
+	if len(pieceString) == 1 {
+		drop, ok := types.ShogiDropCharToMochiPiece[pieceString[0]]
+		return drop, ok
+	}
+
+	//kings and promoted checkers never come back out of the hand
+	pieceType, ok := types.FenStringToPiece[pieceString]
+	handPiece, isHand := types.PieceToHandPiece[pieceType]
+	if !ok || !isHand || types.HandPieceToDropPiece[handPiece] != pieceType {
+		return 0, false
+	}
+
+	return types.HandDropOffset + handPiece, true
+}
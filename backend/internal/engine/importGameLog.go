@@ -0,0 +1,364 @@
+package engine
+
+import (
+	"fmt"
+	"github.com/KainoaGardner/csc/internal/types"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var tagRegex = regexp.MustCompile(`^\[(\w+)\s+"(.*)"\]$`)
+var moveNumberRegex = regexp.MustCompile(`^\d+\.(\.\.)?`)
+var clockRegex = regexp.MustCompile(`\[%clk\s+(\d+):(\d{2}):(\d{2})(?:\.(\d{3}))?\]`)
+
+var results = map[string]bool{
+	"1-0":     true,
+	"0-1":     true,
+	"1/2-1/2": true,
+	"*":       true,
+}
+
+func parseTags(record string) (map[string]string, []string, error) {
+	tags := map[string]string{}
+	lines := strings.Split(strings.ReplaceAll(record, "\r\n", "\n"), "\n")
+
+	i := 0
+	for ; i < len(lines); i++ {
+		line := strings.TrimSpace(lines[i])
+		if line == "" {
+			continue
+		}
+		if !strings.HasPrefix(line, "[") {
+			break
+		}
+
+		match := tagRegex.FindStringSubmatch(line)
+		if match == nil {
+			return nil, nil, fmt.Errorf("Invalid tag %s", line)
+		}
+		tags[match[1]] = match[2]
+	}
+
+	return tags, lines[i:], nil
+}
+
+// comments in braces are kept whole, everything else splits on spaces
+func splitMoveText(lines []string) ([]string, error) {
+	var result []string
+	text := strings.Join(lines, " ")
+
+	for i := 0; i < len(text); {
+		switch {
+		case text[i] == ' ' || text[i] == '\t':
+			i++
+		case text[i] == '{':
+			end := strings.IndexByte(text[i:], '}')
+			if end == -1 {
+				return nil, fmt.Errorf("Comment is never closed")
+			}
+			result = append(result, text[i:i+end+1])
+			i += end + 1
+		default:
+			end := strings.IndexAny(text[i:], " \t{")
+			if end == -1 {
+				end = len(text) - i
+			}
+			result = append(result, text[i:i+end])
+			i += end
+		}
+	}
+
+	return result, nil
+}
+
+func parseClock(comment string) (int64, bool) {
+	match := clockRegex.FindStringSubmatch(comment)
+	if match == nil {
+		return 0, false
+	}
+
+	hours, _ := strconv.ParseInt(match[1], 10, 64)
+	minutes, _ := strconv.ParseInt(match[2], 10, 64)
+	seconds, _ := strconv.ParseInt(match[3], 10, 64)
+	ms := int64(0)
+	if match[4] != "" {
+		ms, _ = strconv.ParseInt(match[4], 10, 64)
+	}
+
+	return ((hours*60+minutes)*60+seconds)*1000 + ms, true
+}
+
+// turns e2-e4, e4xd5, P*e5, CN*f3 or e7-e8=Q+ back into the stored e2,e4 form
+func convertNotationToMoveString(notation string) (string, error) {
+	moveString := strings.TrimSuffix(notation, "#")
+	if strings.HasSuffix(moveString, "+") && !strings.HasSuffix(moveString, "=+") {
+		moveString = strings.TrimSuffix(moveString, "+")
+	}
+
+	promote := ""
+	index := strings.Index(moveString, "=")
+	if index != -1 {
+		promote = moveString[index+1:]
+		moveString = moveString[:index]
+		if len(promote) != 1 {
+			return "", fmt.Errorf("Invalid promotion in %s", notation)
+		}
+	}
+
+	index = strings.Index(moveString, "*")
+	if index > 0 && index < len(moveString)-1 {
+		return moveString[:index+1] + "," + moveString[index+1:] + promote, nil
+	}
+
+	index = strings.IndexAny(moveString, "-x")
+	if index <= 0 || index == len(moveString)-1 {
+		return "", fmt.Errorf("Invalid move %s", notation)
+	}
+
+	return moveString[:index] + "," + moveString[index+1:] + promote, nil
+}
+
+func parseTimeControlString(timeControlString string) (types.TimeControl, error) {
+	var result types.TimeControl
+	var err error
+
+	switch {
+	case timeControlString == "" || timeControlString == "Sudden death":
+		result.Type = types.SuddenDeath
+	case strings.HasPrefix(timeControlString, "Fischer"):
+		result.Type = types.FischerIncrement
+		_, err = fmt.Sscanf(timeControlString, "Fischer %d", &result.Increment)
+	case strings.HasPrefix(timeControlString, "Bronstein"):
+		result.Type = types.BronsteinDelay
+		_, err = fmt.Sscanf(timeControlString, "Bronstein %d", &result.Increment)
+	case strings.HasPrefix(timeControlString, "Simple delay"):
+		result.Type = types.SimpleDelay
+		_, err = fmt.Sscanf(timeControlString, "Simple delay %d", &result.Increment)
+	case strings.HasPrefix(timeControlString, "Byoyomi"):
+		result.Type = types.Byoyomi
+		_, err = fmt.Sscanf(timeControlString, "Byoyomi %dx%d", &result.Periods, &result.PeriodTime)
+	default:
+		return result, fmt.Errorf("Invalid time control %s", timeControlString)
+	}
+	if err != nil {
+		return result, fmt.Errorf("Invalid time control %s", timeControlString)
+	}
+
+	result.Increment *= 1000
+	result.PeriodTime *= 1000
+	return result, nil
+}
+
+func parseMoneyString(moneyString string) ([2]int, error) {
+	var result [2]int
+	if moneyString == "" {
+		return result, nil
+	}
+
+	_, err := fmt.Sscanf(moneyString, "%d/%d", &result[types.White], &result[types.Black])
+	if err != nil {
+		return result, fmt.Errorf("Invalid money %s", moneyString)
+	}
+
+	return result, nil
+}
+
+func setupImportedGameLog(tags map[string]string, game types.Game) (*types.GameLog, error) {
+	var result types.GameLog
+	var err error
+
+	result.WhiteID = tags["WhiteID"]
+	result.BlackID = tags["BlackID"]
+	result.StartFEN = tags["FEN"]
+	result.BoardWidth = game.Board.Width
+	result.BoardHeight = game.Board.Height
+	result.Moves = []string{}
+	result.BoardStates = []string{}
+
+	boardSize := tags["BoardSize"]
+	if boardSize != "" && boardSize != fmt.Sprintf("%dx%d", game.Board.Width, game.Board.Height) {
+		return nil, fmt.Errorf("BoardSize %s does not match the starting position", boardSize)
+	}
+
+	placeLine := tags["PlaceLine"]
+	if placeLine != "" {
+		result.BoardPlaceLine, err = strconv.Atoi(placeLine)
+		if err != nil || result.BoardPlaceLine <= 0 || result.BoardPlaceLine >= game.Board.Height {
+			return nil, fmt.Errorf("Invalid PlaceLine %s", placeLine)
+		}
+	}
+
+	result.Money, err = parseMoneyString(tags["Money"])
+	if err != nil {
+		return nil, err
+	}
+
+	result.TimeControl, err = parseTimeControlString(tags["TimeControl"])
+	if err != nil {
+		return nil, err
+	}
+
+	date := tags["Date"]
+	if date != "" {
+		result.Date, err = time.Parse("2006.01.02", date)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid Date %s", date)
+		}
+	}
+
+	return &result, nil
+}
+
+func getWinnerFromResult(result string) *int {
+	var winner int
+	switch result {
+	case "1-0":
+		winner = types.White
+	case "0-1":
+		winner = types.Black
+	case "1/2-1/2":
+		winner = types.Tie
+	default:
+		return nil
+	}
+
+	return &winner
+}
+
+// checks every move by replaying it, the notation has to match what the replay produces
+func ImportGameLog(record string) (*types.GameLog, error) {
+	tags, moveLines, err := parseTags(record)
+	if err != nil {
+		return nil, err
+	}
+
+	rules := types.DefaultRuleSet
+	err = parseChangedRulesString(tags["DisabledRules"], false, &rules)
+	if err != nil {
+		return nil, err
+	}
+	err = parseChangedRulesString(tags["EnabledRules"], true, &rules)
+	if err != nil {
+		return nil, err
+	}
+	rules.ForcedCapture, err = parseForcedCaptureString(tags["ForcedCapture"])
+	if err != nil {
+		return nil, err
+	}
+	rules.RepetitionRule, err = parseRepetitionRuleString(tags["RepetitionRule"])
+	if err != nil {
+		return nil, err
+	}
+
+	game, err := setupReplayGame(tags["FEN"], &rules)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := setupImportedGameLog(tags, *game)
+	if err != nil {
+		return nil, err
+	}
+	result.Rules = &rules
+
+	tokens, err := splitMoveText(moveLines)
+	if err != nil {
+		return nil, err
+	}
+
+	moveResult := ""
+	lastTurn := -1
+	for _, token := range tokens {
+		if moveResult != "" {
+			return nil, fmt.Errorf("Nothing can follow the result %s", moveResult)
+		}
+
+		if strings.HasPrefix(token, "{") {
+			ms, ok := parseClock(token)
+			if !ok {
+				continue
+			}
+			if lastTurn == -1 {
+				return nil, fmt.Errorf("Clock %s must follow a move", token)
+			}
+
+			game.Time[lastTurn] = ms
+			fen, err := ConvertBoardToString(*game)
+			if err != nil {
+				return nil, err
+			}
+			result.BoardStates[len(result.BoardStates)-1] = fen
+			continue
+		}
+
+		if results[token] {
+			moveResult = token
+			continue
+		}
+
+		token = moveNumberRegex.ReplaceAllString(token, "")
+		if token == "" {
+			continue
+		}
+
+		moveNumber := len(result.Moves) + 1
+		moveString, err := convertNotationToMoveString(token)
+		if err != nil {
+			return nil, fmt.Errorf("Move %d: %v", moveNumber, err)
+		}
+
+		lastTurn = game.Turn
+		move, before, err := replayMove(moveString, game)
+		if err != nil {
+			return nil, fmt.Errorf("Move %d %s: %v", moveNumber, token, err)
+		}
+
+		notation, err := getMoveNotation(move, *before, *game)
+		if err != nil {
+			return nil, err
+		}
+		if notation != token {
+			return nil, fmt.Errorf("Move %d %s should be written %s", moveNumber, token, notation)
+		}
+
+		fen, err := ConvertBoardToString(*game)
+		if err != nil {
+			return nil, err
+		}
+		result.Moves = append(result.Moves, moveString)
+		result.BoardStates = append(result.BoardStates, fen)
+	}
+
+	resultTag := tags["Result"]
+	if resultTag != "" && !results[resultTag] {
+		return nil, fmt.Errorf("Invalid Result %s", resultTag)
+	}
+	if resultTag != "" && moveResult != "" && resultTag != moveResult {
+		return nil, fmt.Errorf("Result %s does not match the moves result %s", resultTag, moveResult)
+	}
+	if resultTag == "" {
+		resultTag = moveResult
+	}
+
+	result.MoveCount = game.MoveCount
+	result.Reason = tags["Reason"]
+	result.Winner = getWinnerFromResult(resultTag)
+
+	//a game decided on the board has to be recorded the same way
+	if game.State == types.OverState {
+		replayResult := getResultString(game.Winner)
+		if resultTag != "" && resultTag != replayResult {
+			return nil, fmt.Errorf("Result %s does not match the final position %s", resultTag, replayResult)
+		}
+		if result.Reason != "" && result.Reason != game.Reason {
+			return nil, fmt.Errorf("Reason %s does not match the final position %s", result.Reason, game.Reason)
+		}
+
+		result.Winner = game.Winner
+		result.Reason = game.Reason
+	}
+
+	return result, nil
+}
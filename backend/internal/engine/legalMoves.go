@@ -0,0 +1,122 @@
+package engine
+
+import (
+	"github.com/KainoaGardner/csc/internal/types"
+	"github.com/KainoaGardner/csc/internal/utils"
+)
+
+func LegalMoves(game types.Game) []types.Move {
+	result := []types.Move{}
+
+	if game.State != types.MoveState || game.Winner != nil {
+		return result
+	}
+
+	for i := 0; i < game.Board.Height; i++ {
+		for j := 0; j < game.Board.Width; j++ {
+			space := game.Board.Board[i][j]
+			if space == nil || space.Owner != game.Turn {
+				continue
+			}
+
+			pos := types.Vec2{X: j, Y: i}
+			if game.CheckerJump != nil && !utils.CheckVec2Equal(pos, *game.CheckerJump) {
+				continue
+			}
+
+			result = append(result, getLegalPieceMoves(pos, *space, game)...)
+		}
+	}
+
+	if game.CheckerJump == nil {
+		result = append(result, getLegalDrops(game)...)
+	}
+
+	return filterForcedCapture(result, game)
+}
+
+func LegalMoveStrings(game types.Game) ([]string, error) {
+	result := []string{}
+
+	for _, move := range LegalMoves(game) {
+		moveString, err := ConvertMoveToString(move, game)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, moveString)
+	}
+
+	return result, nil
+}
+
+func getLegalPieceMoves(pos types.Vec2, piece types.Piece, game types.Game) []types.Move {
+	var result []types.Move
+
+	dir := getMoveDirection(game)
+	possibleMoves := getPieceMoves(pos, piece, game, dir)
+	filterPossibleMoves(pos, &possibleMoves, game)
+
+	for _, end := range possibleMoves {
+		for _, promote := range getPromoteOptions(piece) {
+			move := types.Move{
+				Start:   pos,
+				End:     end,
+				Promote: promote,
+			}
+
+			if checkValidMovePromote(move, piece, game) == nil {
+				result = append(result, move)
+			}
+		}
+	}
+
+	return result
+}
+
+func getPromoteOptions(piece types.Piece) []*int {
+	result := []*int{nil}
+
+	if piece.Type == types.Pawn {
+		for _, promoteType := range []int{types.Knight, types.Bishop, types.Rook, types.Queen} {
+			result = append(result, &promoteType)
+		}
+		return result
+	}
+
+	_, shogiPromote := types.ShogiPieceToPromotePiece[piece.Type]
+	if piece.Type == types.Checker || shogiPromote {
+		promote := 0
+		result = append(result, &promote)
+	}
+
+	return result
+}
+
+func getLegalDrops(game types.Game) []types.Move {
+	var result []types.Move
+
+	for _, drop := range getAllDrops() {
+		if getDropCount(drop, game) <= 0 {
+			continue
+		}
+
+		for i := 0; i < game.Board.Height; i++ {
+			for j := 0; j < game.Board.Width; j++ {
+				if game.Board.Board[i][j] != nil {
+					continue
+				}
+
+				move := types.Move{
+					End:  types.Vec2{X: j, Y: i},
+					Drop: &drop,
+				}
+
+				if checkValidMove(move, game) == nil {
+					result = append(result, move)
+				}
+			}
+		}
+	}
+
+	return result
+}
@@ -22,25 +22,43 @@ func MovePiece(move types.Move, game *types.Game) error {
 		return err
 	}
 
-	piece, err := getPiece(move, *game)
+	err = updateStateHistory(game)
 	if err != nil {
 		return err
 	}
+	game.Takeback = [2]bool{false, false}
 
-	updateMoveTime(game)
-	if checkTimeLoss(*game) {
-		moveTurn := getEnemyTurnInt(*game)
-		game.Winner = &moveTurn
-		game.Reason = "Time"
-		game.State = types.OverState
+	timeLoss := updateMoveTime(game)
+	if timeLoss {
+		setupTimeLoss(game)
+		armClock(*game)
 		return nil
 	}
 
+	err = applyMove(move, game)
+	if err != nil {
+		return err
+	}
+
+	armClock(*game)
+	return nil
+}
+
+func applyMove(move types.Move, game *types.Game) error {
+	piece, err := getPiece(move, *game)
+	if err != nil {
+		return err
+	}
+
 	dir := getMoveDirection(*game)
 	takePiece := getTakePiece(move, *game, piece, dir)
-	doMovePiece(game, move, piece, takePiece, dir)
+	checkerTake := checkCheckerPieceTake(move, *piece, *game)
+	err = doMovePiece(game, move, piece, takePiece, dir)
+	if err != nil {
+		return err
+	}
 
-	if checkCheckerNextJumps(move.Start, move.End, *piece, *game) {
+	if checkerTake && checkCheckerNextJumps(move.End, *piece, *game) {
 		game.CheckerJump = &move.End
 	} else {
 		game.CheckerJump = nil
@@ -57,7 +75,7 @@ func MovePiece(move types.Move, game *types.Game) error {
 }
 
 func doMovePiece(game *types.Game, move types.Move, piece *types.Piece, takePiece *types.Piece, dir int) error {
-	validCastle := takePiece != nil && piece.Type == types.King && takePiece.Type == types.Rook && takePiece.Owner == piece.Owner
+	validCastle := checkValidCastle(piece, takePiece, *game)
 
 	updateEndPosition(move, game, piece, takePiece, validCastle)
 
@@ -67,31 +85,44 @@ func doMovePiece(game *types.Game, move types.Move, piece *types.Piece, takePiec
 	updateRemoveCheckerTakePiece(move, game, piece, dir)
 
 	offset := getMochigomaOffset(*game)
-	updateRemoveStartPosition(move, game, offset, validCastle)
+	updateRemoveStartPosition(move, game, validCastle)
 
 	err := updateMochigoma(takePiece, game, offset)
 	if err != nil {
 		return err
 	}
+	updateHand(takePiece, game)
 
 	return nil
 }
 
 func getTakePiece(move types.Move, game types.Game, piece *types.Piece, dir int) *types.Piece {
-	if checkCheckerTake(move.Start, move.End) {
-		jumpDir := getCastleDirection(move)
-		return game.Board.Board[move.Start.Y-dir][move.Start.X+jumpDir]
+	if checkCheckerPieceTake(move, *piece, game) {
+		takePos := getCheckerTakePos(move.Start, move.End, game)
+		return game.Board.Board[takePos.Y][takePos.X]
 	} else if checkEnPassantTake(move, game, piece) {
 		return game.Board.Board[move.End.Y+dir][move.End.X]
-	} else if checkCheckerTake(move.Start, move.End) {
-		dir := getCheckerJumpDir(move)
-		takePos := types.Vec2{X: move.Start.X + dir.X, Y: move.Start.Y + dir.Y}
-		return game.Board.Board[takePos.Y][takePos.X]
 	} else {
 		return game.Board.Board[move.End.Y][move.End.X]
 	}
 }
 
+func checkCheckerPiece(piece types.Piece) bool {
+	return piece.Type == types.Checker || piece.Type == types.CheckerKing
+}
+
+func checkCheckerPieceTake(move types.Move, piece types.Piece, game types.Game) bool {
+	if move.Drop != nil {
+		return false
+	}
+
+	if !checkCheckerPiece(piece) {
+		return false
+	}
+
+	return getCheckerTakePos(move.Start, move.End, game) != nil
+}
+
 func checkCheckmateOrDraw(game *types.Game) error {
 	if GetInCheckmate(*game) {
 		moveTurn := getEnemyTurnInt(*game)
@@ -99,13 +130,12 @@ func checkCheckmateOrDraw(game *types.Game) error {
 		game.Reason = "Checkmate"
 		game.State = types.OverState
 	} else {
-		result, reason, err := GetDraw(game)
+		result, winner, reason, err := GetDraw(game)
 		if err != nil {
 			return err
 		}
 		if result {
-			tie := types.Tie
-			game.Winner = &tie
+			game.Winner = &winner
 			game.Reason = reason
 			game.State = types.OverState
 		}
@@ -129,6 +159,11 @@ func checkValidMove(move types.Move, game types.Game) error {
 		return err
 	}
 
+	err = checkForcedCapture(move, *piece, game)
+	if err != nil {
+		return err
+	}
+
 	if move.Drop != nil { //drop
 		err = checkValidDrop(move, *piece, game)
 		if err != nil {
@@ -141,13 +176,22 @@ func checkValidMove(move types.Move, game types.Game) error {
 		}
 	}
 
+	err = checkValidMovePromote(move, *piece, game)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func checkValidMovePromote(move types.Move, piece types.Piece, game types.Game) error {
 	if move.Promote != nil {
-		err = checkValidPromote(move, *piece, game)
+		err := checkValidPromote(move, piece, game)
 		if err != nil {
 			return err
 		}
 	} else {
-		err = checkMustPromote(move, *piece, game) //pawn checker last row must promote
+		err := checkMustPromote(move, piece, game) //pawn checker last row must promote
 		if err != nil {
 			return err
 		}
@@ -177,14 +221,14 @@ func getPiece(move types.Move, game types.Game) (*types.Piece, error) {
 	var piece *types.Piece
 	if move.Drop != nil {
 		var dropPiece types.Piece
-		mochigoma := *move.Drop
-		koma, ok := types.ShogiMochiPieceToDropPiece[mochigoma]
+		koma, ok := getDropPieceType(*move.Drop)
 		if !ok {
 			return piece, fmt.Errorf("Could not fight correct piece from drop mochigoma")
 		}
 
 		dropPiece.Type = koma
 		dropPiece.Owner = game.Turn
+		dropPiece.Moved = *move.Drop >= types.HandDropOffset //dropped rooks cant castle
 		piece = &dropPiece
 
 	} else {
@@ -220,6 +264,13 @@ func getEnemyTurnInt(game types.Game) int {
 	}
 }
 
+func getOtherTurn(turn int) int {
+	if turn == types.White {
+		return types.Black
+	}
+	return types.White
+}
+
 func checkCheckerJumpMove(move types.Move, game types.Game) error {
 	if game.CheckerJump == nil {
 		return nil
@@ -255,7 +306,7 @@ func updateMoveCount(game *types.Game) {
 }
 
 func updateEnPassantPosition(piece *types.Piece, move types.Move, game *types.Game, dir int) {
-	if piece.Type == types.Pawn && utils.AbsoluteValueInt(move.Start.Y-move.End.Y) == 2 {
+	if GetRules(*game).EnPassant && piece.Type == types.Pawn && utils.AbsoluteValueInt(move.Start.Y-move.End.Y) == 2 {
 		game.EnPassant = &types.Vec2{X: move.Start.X, Y: move.Start.Y - dir}
 	} else {
 		game.EnPassant = nil
@@ -273,9 +324,9 @@ func updateMochigoma(takePiece *types.Piece, game *types.Game, offset int) error
 	return nil
 }
 
-func updateRemoveStartPosition(move types.Move, game *types.Game, offset int, validCastle bool) {
+func updateRemoveStartPosition(move types.Move, game *types.Game, validCastle bool) {
 	if move.Drop != nil {
-		game.Mochigoma[*move.Drop+offset]--
+		removeDropPiece(*move.Drop, game)
 	} else if validCastle {
 		game.Board.Board[move.Start.Y][move.Start.X] = nil
 		game.Board.Board[move.End.Y][move.End.X] = nil
@@ -285,7 +336,7 @@ func updateRemoveStartPosition(move types.Move, game *types.Game, offset int, va
 }
 
 func checkEnPassantTake(move types.Move, game types.Game, piece *types.Piece) bool {
-	if game.EnPassant != nil && utils.CheckVec2Equal(move.End, *game.EnPassant) && piece.Type == types.Pawn {
+	if GetRules(game).EnPassant && game.EnPassant != nil && utils.CheckVec2Equal(move.End, *game.EnPassant) && piece.Type == types.Pawn {
 		return true
 	}
 	return false
@@ -298,12 +349,8 @@ func updateEnPassantTakePosition(move types.Move, game *types.Game, piece *types
 }
 
 func updateRemoveCheckerTakePiece(move types.Move, game *types.Game, piece *types.Piece, dir int) {
-	if checkCheckerTake(move.Start, move.End) {
-		dir := getCheckerJumpDir(move)
-		takePos := types.Vec2{
-			X: move.Start.X + dir.X,
-			Y: move.Start.Y + dir.Y,
-		}
+	if checkCheckerPieceTake(move, *piece, *game) {
+		takePos := getCheckerTakePos(move.Start, move.End, *game)
 		game.Board.Board[takePos.Y][takePos.X] = nil
 	}
 }
@@ -324,6 +371,11 @@ func getCheckerJumpDir(move types.Move) types.Vec2 {
 	return result
 }
 
+// the king moving onto its own rook castles
+func checkValidCastle(piece *types.Piece, takePiece *types.Piece, game types.Game) bool {
+	return GetRules(game).Castling && takePiece != nil && piece.Type == types.King && takePiece.Type == types.Rook && takePiece.Owner == piece.Owner
+}
+
 func getCastleDirection(move types.Move) int {
 	if move.End.X < move.Start.X {
 		return -1
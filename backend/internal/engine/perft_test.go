@@ -0,0 +1,60 @@
+package engine
+
+import (
+	"testing"
+)
+
+type perftPosition struct {
+	name   string
+	fen    string
+	counts []int //expected leaf counts for depth 1..n
+}
+
+var perftPositions = []perftPosition{
+	{
+		name:   "chess start",
+		fen:    "cr*cn*cb*cq*ck*cb*cn*cr*/cp*cp*cp*cp*cp*cp*cp*cp*/8/8/8/8/CP*CP*CP*CP*CP*CP*CP*CP*/CR*CN*CB*CQ*CK*CB*CN*CR* 0/0/0/0/0/0/0/0/0/0/0/0/0/0 w - - 0 0 0/0",
+		counts: []int{20, 400, 8902},
+	},
+	{
+		name:   "mixed armies",
+		fen:    "cr*sn*sg*sk*sc*sb*kc*cq*/sp*sp*cp*cp*kc*1kc*1/8/8/8/8/KC*1KC*1SP*SP*CP*CP*/CQ*CN*SG*CK*SC*SR*SL*CR* 0/0/0/0/0/0/0/0/0/0/0/0/0/0 w - - 0 0 0/0",
+		counts: []int{22, 458, 11641},
+	},
+	{
+		name:   "shogi with mochigoma",
+		fen:    "sr*sb*sg*sc*sk*/4sp*/5/SP*4/SK*SC*SG*SB*SR* 1/0/0/0/0/0/0/1/0/0/0/0/0/0 w - - 0 0 0/0",
+		counts: []int{30, 804, 19358},
+	},
+	{
+		name:   "checkers multi jump",
+		fen:    "4ck*3/8/8/kc*7/3kc*4/6KK-1/1kc*6/KC*3CK*3 0/0/0/0/0/0/0/0/0/0/0/0/0/0 w - - 0 0 0/0",
+		counts: []int{10, 82, 816},
+	},
+}
+
+func TestPerft(t *testing.T) {
+	for _, position := range perftPositions {
+		t.Run(position.name, func(t *testing.T) {
+			game := loadTestGame(t, position.fen)
+
+			for i, expected := range position.counts {
+				depth := i + 1
+				count, err := Perft(*game, depth)
+				if err != nil {
+					t.Fatalf("depth %d: %v", depth, err)
+				}
+				if count != expected {
+					t.Errorf("depth %d: got %d moves, expected %d", depth, count, expected)
+				}
+			}
+		})
+	}
+}
+
+func TestMoveStringRoundTrip(t *testing.T) {
+	moves, err := MovesTest()
+	if err != nil {
+		t.Fatalf("%v after %v", err, moves)
+	}
+}
@@ -3,6 +3,7 @@ package engine
 import (
 	"fmt"
 	"github.com/KainoaGardner/csc/internal/types"
+	"time"
 )
 
 func PlacePiece(place types.Place, game *types.Game) error {
@@ -17,7 +18,7 @@ func PlacePiece(place types.Place, game *types.Game) error {
 	}
 
 	updatePlacePiece(place, game)
-	return nil
+	return recordPlacement(types.CreatePlaceEnum, place, place.Cost, game)
 }
 
 func PlacePieceDelete(place *types.Place, game *types.Game) error {
@@ -31,9 +32,12 @@ func PlacePieceDelete(place *types.Place, game *types.Game) error {
 		return err
 	}
 
-	updateDeletePlacePiece(place, game)
+	err = updateDeletePlacePiece(place, game)
+	if err != nil {
+		return err
+	}
 
-	return nil
+	return recordPlacement(types.DeletePlaceEnum, *place, -place.Cost, game)
 }
 
 func PlacePieceMove(place *types.Place, game *types.Game) error {
@@ -47,8 +51,41 @@ func PlacePieceMove(place *types.Place, game *types.Game) error {
 		return err
 	}
 
-	updateMovePlacePiece(*place, game)
+	updateMovePlacePiece(place, game)
+
+	return recordPlacement(types.MovePlaceEnum, *place, 0, game)
+}
+
+func recordPlacement(action int, place types.Place, cost int, game *types.Game) error {
+	position, err := convertPositionToString(place.Pos, *game)
+	if err != nil {
+		return err
+	}
+
+	fen, err := ConvertBoardToString(*game)
+	if err != nil {
+		return err
+	}
+
+	record := types.PlacementRecord{
+		Turn:     place.Turn,
+		Action:   action,
+		Type:     place.Type,
+		Position: position,
+		Cost:     cost,
+		Money:    game.Money[place.Turn],
+		FEN:      fen,
+		Date:     time.Now().UTC(),
+	}
+
+	if place.From != nil {
+		record.From, err = convertPositionToString(*place.From, *game)
+		if err != nil {
+			return err
+		}
+	}
 
+	game.Placements = append(game.Placements, record)
 	return nil
 }
 
@@ -95,9 +132,9 @@ func updateDeletePlacePiece(place *types.Place, game *types.Game) error {
 
 	place.Type = piece.Type
 
-	cost, ok := types.PieceToCost[place.Type]
-	if !ok {
-		return fmt.Errorf("Could not get cost of piece")
+	cost, err := getPieceCost(place.Type, *game)
+	if err != nil {
+		return err
 	}
 
 	place.Cost = cost
@@ -121,8 +158,9 @@ func checkValidPlaceDelete(place types.Place, game types.Game) error {
 	return nil
 }
 
-func updateMovePlacePiece(place types.Place, game *types.Game) {
+func updateMovePlacePiece(place *types.Place, game *types.Game) {
 	piece := game.Board.Board[place.From.Y][place.From.X]
+	place.Type = piece.Type
 
 	game.Board.Board[place.Pos.Y][place.Pos.X] = piece
 	game.Board.Board[place.From.Y][place.From.X] = nil
@@ -183,7 +221,16 @@ func checkPlayerPiece(place types.Place, game types.Game) error {
 }
 
 func checkEnoughMoney(place types.Place, game types.Game) error {
-	if game.Money[place.Turn]-place.Cost < 0 {
+	cost, err := getPieceCost(place.Type, game)
+	if err != nil {
+		return err
+	}
+
+	if cost != place.Cost {
+		return fmt.Errorf("Piece cost does not match the game prices")
+	}
+
+	if game.Money[place.Turn]-cost < 0 {
 		return fmt.Errorf("Not enough money")
 	}
 
@@ -238,26 +285,12 @@ func checkEmptyPlaceSpace(place types.Place, game types.Game) error {
 	return nil
 }
 
-func checkValidPlaceType(place types.PostPlace) error {
-	if place.Type >= types.Pawn && place.Type <= types.King {
-		return nil
-	}
-	if place.Type >= types.Fu && place.Type <= types.Ou {
-		return nil
-	}
-	if place.Type >= types.Checker && place.Type <= types.CheckerKing {
-		return nil
-	}
-
-	return fmt.Errorf("Invalid place type")
-}
-
 func SetupPlace(placeConfig types.PostPlace, turn int, game types.Game) (types.Place, error) {
 	var result types.Place
 
 	result.Turn = turn
 
-	err := checkValidPlaceType(placeConfig)
+	err := checkPieceType(placeConfig.Type)
 	if err != nil {
 		return result, err
 	}
@@ -269,9 +302,9 @@ func SetupPlace(placeConfig types.PostPlace, turn int, game types.Game) (types.P
 	}
 	result.Pos = position
 
-	cost, ok := types.PieceToCost[result.Type]
-	if !ok {
-		return result, fmt.Errorf("Could not get cost of piece")
+	cost, err := getPieceCost(result.Type, game)
+	if err != nil {
+		return result, err
 	}
 	result.Cost = cost
 
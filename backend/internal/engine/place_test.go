@@ -0,0 +1,71 @@
+package engine
+
+import (
+	"testing"
+
+	"github.com/KainoaGardner/csc/internal/types"
+)
+
+func TestPlacementRecords(t *testing.T) {
+	postGame := types.PostGame{
+		Width:     4,
+		Height:    4,
+		PlaceLine: 2,
+		Money:     [2]int{100, 100},
+		StartTime: [2]int64{600, 600},
+	}
+	game, err := SetupNewGame(postGame, "white")
+	if err != nil {
+		t.Fatal(err)
+	}
+	game.State = types.PlaceState
+
+	place, err := SetupPlace(types.PostPlace{Position: "a1", Type: types.Queen}, types.White, *game)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := PlacePiece(place, game); err != nil {
+		t.Fatal(err)
+	}
+
+	place, err = SetupMovePlace(types.PostPlace{Position: "b2", FromPosition: "a1"}, types.White, *game)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := PlacePieceMove(&place, game); err != nil {
+		t.Fatal(err)
+	}
+
+	place, err = SetupDeletePlace(types.PostPlace{Position: "b2"}, types.White, *game)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := PlacePieceDelete(&place, game); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []types.PlacementRecord{
+		{Turn: types.White, Action: types.CreatePlaceEnum, Type: types.Queen, Position: "a1", Cost: 50, Money: 50},
+		{Turn: types.White, Action: types.MovePlaceEnum, Type: types.Queen, Position: "b2", From: "a1", Cost: 0, Money: 50},
+		{Turn: types.White, Action: types.DeletePlaceEnum, Type: types.Queen, Position: "b2", Cost: -50, Money: 100},
+	}
+	if len(game.Placements) != len(expected) {
+		t.Fatalf("got %d placements, expected %d", len(game.Placements), len(expected))
+	}
+
+	for i, record := range game.Placements {
+		if record.FEN == "" {
+			t.Errorf("placement %d has no fen", i)
+		}
+		record.FEN = ""
+		record.Date = expected[i].Date
+		if record != expected[i] {
+			t.Errorf("placement %d got %+v, expected %+v", i, record, expected[i])
+		}
+	}
+
+	gameLog := SetupGameLog(*game, "")
+	if len(gameLog.Placements) != len(expected) {
+		t.Errorf("game log should keep all %d placements", len(expected))
+	}
+}
@@ -0,0 +1,92 @@
+package engine
+
+import (
+	"fmt"
+	"github.com/KainoaGardner/csc/internal/types"
+	"maps"
+)
+
+const maxPieceCost = 100000000
+
+// games saved before prices were stored use the default prices
+func GetPrices(game types.Game) map[int]int {
+	if game.Prices == nil {
+		return types.PieceToCost
+	}
+
+	return game.Prices
+}
+
+func getPieceCost(pieceType int, game types.Game) (int, error) {
+	err := checkPieceType(pieceType)
+	if err != nil {
+		return 0, err
+	}
+
+	cost, ok := GetPrices(game)[pieceType]
+	if !ok {
+		return 0, fmt.Errorf("Piece type %d cannot be bought in this game", pieceType)
+	}
+
+	return cost, nil
+}
+
+func checkPieceType(pieceType int) error {
+	if pieceType < types.Pawn || pieceType > types.CheckerKing {
+		return fmt.Errorf("Invalid piece type %d", pieceType)
+	}
+
+	return nil
+}
+
+// defaults with the custom prices on top and the disallowed types removed
+func setupPrices(gameConfig types.PostGame) map[int]int {
+	result := maps.Clone(types.PieceToCost)
+	maps.Copy(result, gameConfig.Prices)
+
+	for _, pieceType := range gameConfig.Disallowed {
+		delete(result, pieceType)
+	}
+
+	return result
+}
+
+func checkPricesConfig(gameConfig types.PostGame) error {
+	for pieceType, cost := range gameConfig.Prices {
+		err := checkPieceType(pieceType)
+		if err != nil {
+			return err
+		}
+
+		if cost <= 0 || cost > maxPieceCost {
+			return fmt.Errorf("Piece cost must be between 1 and %d", maxPieceCost)
+		}
+	}
+
+	for _, pieceType := range gameConfig.Disallowed {
+		err := checkPieceType(pieceType)
+		if err != nil {
+			return err
+		}
+	}
+
+	//both players have to be able to buy a king to ready up
+	prices := setupPrices(gameConfig)
+	kingCost := -1
+	for _, king := range []int{types.King, types.Ou} {
+		cost, ok := prices[king]
+		if ok && (kingCost == -1 || cost < kingCost) {
+			kingCost = cost
+		}
+	}
+
+	if kingCost == -1 {
+		return fmt.Errorf("King and Ou cannot both be disallowed")
+	}
+
+	if kingCost > gameConfig.Money[types.White] || kingCost > gameConfig.Money[types.Black] {
+		return fmt.Errorf("Both players need enough money for a king")
+	}
+
+	return nil
+}
@@ -0,0 +1,162 @@
+package engine
+
+import (
+	"testing"
+
+	"github.com/KainoaGardner/csc/internal/store"
+	"github.com/KainoaGardner/csc/internal/types"
+)
+
+func setupTestPricesGame(t *testing.T, prices map[int]int, disallowed []int) *types.Game {
+	t.Helper()
+
+	postGame := types.PostGame{
+		Width:      8,
+		Height:     8,
+		PlaceLine:  4,
+		Money:      [2]int{100, 100},
+		StartTime:  [2]int64{600, 600},
+		Prices:     prices,
+		Disallowed: disallowed,
+	}
+	game, err := SetupNewGame(postGame, "white")
+	if err != nil {
+		t.Fatal(err)
+	}
+	game.State = types.PlaceState
+
+	return game
+}
+
+func TestGamePrices(t *testing.T) {
+	game := setupTestPricesGame(t, map[int]int{types.Queen: 10, types.CheckerKing: 15}, []int{types.Knight})
+
+	cases := []struct {
+		name      string
+		pieceType int
+		cost      int
+		ok        bool
+	}{
+		{"custom price", types.Queen, 10, true},
+		{"default price", types.Rook, types.PieceToCost[types.Rook], true},
+		{"newly sold piece", types.CheckerKing, 15, true},
+		{"disallowed", types.Knight, 0, false},
+		{"not sold by default", types.Ryuu, 0, false},
+		{"not a piece", 99, 0, false},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			place, err := SetupPlace(types.PostPlace{Position: "a1", Type: c.pieceType}, types.White, *game)
+			if !c.ok {
+				if err == nil {
+					t.Errorf("should not be able to buy %d", c.pieceType)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if place.Cost != c.cost {
+				t.Errorf("got cost %d, expected %d", place.Cost, c.cost)
+			}
+		})
+	}
+
+	place, err := SetupPlace(types.PostPlace{Position: "a1", Type: types.Queen}, types.White, *game)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	place.Cost = types.PieceToCost[types.Queen]
+	err = PlacePiece(place, game)
+	if err == nil {
+		t.Errorf("placing at the default price should fail")
+	}
+
+	place.Cost = 10
+	err = PlacePiece(place, game)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	place, err = SetupDeletePlace(types.PostPlace{Position: "a1"}, types.White, *game)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = PlacePieceDelete(&place, game)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if game.Money[types.White] != 100 {
+		t.Errorf("deleting should refund the game price, got %d money", game.Money[types.White])
+	}
+}
+
+func TestGamePricesConfig(t *testing.T) {
+	cases := []struct {
+		name       string
+		prices     map[int]int
+		disallowed []int
+	}{
+		{"zero cost", map[int]int{types.Pawn: 0}, nil},
+		{"not a piece", map[int]int{99: 5}, nil},
+		{"disallow not a piece", nil, []int{-1}},
+		{"no kings", nil, []int{types.King, types.Ou}},
+		{"king too expensive", map[int]int{types.King: 200, types.Ou: 150}, nil},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			postGame := types.PostGame{
+				Width:      8,
+				Height:     8,
+				PlaceLine:  4,
+				Money:      [2]int{100, 100},
+				Prices:     c.prices,
+				Disallowed: c.disallowed,
+			}
+			_, err := SetupNewGame(postGame, "white")
+			if err == nil {
+				t.Errorf("game should not be created")
+			}
+		})
+	}
+
+	game := setupTestPricesGame(t, nil, []int{types.King})
+	army, err := PlaceArmy(types.ChessArmy, types.White, game)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if army.Pieces[0].Type != types.Ou {
+		t.Errorf("chess army should fall back to an ou, got %+v", army.Pieces[0])
+	}
+}
+
+func TestGamePricesStored(t *testing.T) {
+	gameStore := store.NewMemoryStore()
+
+	game := setupTestPricesGame(t, map[int]int{types.Pawn: 7}, []int{types.Queen})
+	gameID, err := gameStore.CreateGame(game)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	stored, err := gameStore.FindGame(gameID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	prices := GetPrices(*stored)
+	if prices[types.Pawn] != 7 || prices[types.Rook] != types.PieceToCost[types.Rook] {
+		t.Errorf("got prices %v", prices)
+	}
+	if _, ok := prices[types.Queen]; ok {
+		t.Errorf("queen should stay disallowed, got prices %v", prices)
+	}
+
+	stored.Prices = nil
+	if GetPrices(*stored)[types.Queen] != types.PieceToCost[types.Queen] {
+		t.Errorf("games without prices should use the defaults")
+	}
+}
@@ -37,6 +37,7 @@ func ReadyPlayer(ready bool, turn int, game *types.Game) error {
 		if err != nil {
 			return err
 		}
+		armClock(*game)
 	}
 
 	return nil
@@ -72,13 +73,12 @@ func checkGameOverAtStart(game *types.Game) error {
 		game.Reason = "Checkmate"
 		game.State = types.OverState
 	} else {
-		result, reason, err := GetDraw(game)
+		result, winner, reason, err := GetDraw(game)
 		if err != nil {
 			return err
 		}
 		if result {
-			tie := types.Tie
-			game.Winner = &tie
+			game.Winner = &winner
 			game.Reason = reason
 			game.State = types.OverState
 		}
@@ -0,0 +1,53 @@
+package engine
+
+import (
+	"fmt"
+	"github.com/KainoaGardner/csc/internal/types"
+)
+
+func GetReplayPlies(gameLog types.GameLog) int {
+	return min(len(gameLog.Moves), len(gameLog.BoardStates))
+}
+
+// ply 0 is the position after placement, ply n is the position after the nth move
+func GetReplayPosition(gameLog types.GameLog, ply int) (types.ReplayPositionResponse, error) {
+	var result types.ReplayPositionResponse
+
+	if gameLog.Winner == nil {
+		return result, fmt.Errorf("Game is still in progress")
+	}
+
+	plies := GetReplayPlies(gameLog)
+	if ply < 0 || ply > plies {
+		return result, fmt.Errorf("Ply must be between 0 and %d", plies)
+	}
+
+	fen := gameLog.StartFEN
+	if ply > 0 {
+		fen = gameLog.BoardStates[ply-1]
+		result.LastMove = gameLog.Moves[ply-1]
+	}
+	if fen == "" {
+		return result, fmt.Errorf("Game log has no starting position")
+	}
+
+	game, err := ConvertStringToBoard(fen)
+	if err != nil {
+		return result, err
+	}
+
+	result.ID = gameLog.ID
+	result.Ply = ply
+	result.Plies = plies
+	result.FEN = fen
+	result.Turn = game.Turn
+	result.InCheck = GetInCheck(*game)
+	result.Time = game.Time
+
+	if ply == plies {
+		result.Winner = gameLog.Winner
+		result.Reason = gameLog.Reason
+	}
+
+	return result, nil
+}
@@ -0,0 +1,52 @@
+package engine
+
+import (
+	"testing"
+
+	"github.com/KainoaGardner/csc/internal/types"
+)
+
+func TestGetReplayPosition(t *testing.T) {
+	startFEN := "4ck*3/3cp*4/8/8/8/8/4CP*3/3CQ*CK*3 0/0/0/0/0/0/0/0/0/0/0/0/0/0 w - - 0 0 600000/598500"
+	moves := []string{"e2,e4", "d7,d5", "e4,d5", "e8,d7", "d1,g4"}
+
+	gameLog := setupTestGameLog(t, startFEN, moves)
+
+	_, err := GetReplayPosition(gameLog, 0)
+	if err == nil {
+		t.Errorf("unfinished games should not replay")
+	}
+
+	winner := types.White
+	gameLog.Winner = &winner
+	gameLog.Reason = "Resignation"
+
+	start, err := GetReplayPosition(gameLog, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if start.FEN != startFEN || start.LastMove != "" || start.Turn != types.White || start.Plies != len(moves) {
+		t.Errorf("got start %+v", start)
+	}
+	if start.Time != [2]int64{600000, 598500} || start.Winner != nil {
+		t.Errorf("got start clocks %v winner %v", start.Time, start.Winner)
+	}
+
+	last, err := GetReplayPosition(gameLog, len(moves))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if last.FEN != gameLog.BoardStates[len(moves)-1] || last.LastMove != "d1,g4" || last.Turn != types.Black {
+		t.Errorf("got last %+v", last)
+	}
+	if !last.InCheck || last.Winner == nil || *last.Winner != types.White || last.Reason != "Resignation" {
+		t.Errorf("got last check %v winner %v reason %s", last.InCheck, last.Winner, last.Reason)
+	}
+
+	for _, ply := range []int{-1, len(moves) + 1} {
+		_, err := GetReplayPosition(gameLog, ply)
+		if err == nil {
+			t.Errorf("ply %d should be out of range", ply)
+		}
+	}
+}
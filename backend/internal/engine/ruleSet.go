@@ -0,0 +1,143 @@
+package engine
+
+import (
+	"fmt"
+	"github.com/KainoaGardner/csc/internal/types"
+	"strings"
+)
+
+// games saved before rules were stored play every rule
+func GetRules(game types.Game) types.RuleSet {
+	if game.Rules == nil {
+		return types.DefaultRuleSet
+	}
+
+	return *game.Rules
+}
+
+func setupRules(gameConfig types.PostGame) *types.RuleSet {
+	result := types.DefaultRuleSet
+	post := gameConfig.Rules
+	if post == nil {
+		return &result
+	}
+
+	setRule(&result.Nifu, post.Nifu)
+	setRule(&result.Uchifuzume, post.Uchifuzume)
+	setRule(&result.EnPassant, post.EnPassant)
+	setRule(&result.Castling, post.Castling)
+	setRule(&result.Repetition, post.Repetition)
+	setRule(&result.FiftyMove, post.FiftyMove)
+	setRule(&result.InsufficientMaterial, post.InsufficientMaterial)
+	setRule(&result.ForcedCapture, post.ForcedCapture)
+	setRule(&result.FlyingKings, post.FlyingKings)
+	setRule(&result.BackwardCaptures, post.BackwardCaptures)
+	setRule(&result.Crazyhouse, post.Crazyhouse)
+	setRule(&result.RepetitionRule, post.RepetitionRule)
+
+	return &result
+}
+
+func setRule[T any](rule *T, post *T) {
+	if post != nil {
+		*rule = *post
+	}
+}
+
+type ruleField struct {
+	name string
+	on   *bool
+}
+
+// names used for the DisabledRules and EnabledRules tags of exported logs
+func getRuleFields(rules *types.RuleSet) []ruleField {
+	return []ruleField{
+		{"Nifu", &rules.Nifu},
+		{"Uchifuzume", &rules.Uchifuzume},
+		{"EnPassant", &rules.EnPassant},
+		{"Castling", &rules.Castling},
+		{"Repetition", &rules.Repetition},
+		{"FiftyMove", &rules.FiftyMove},
+		{"InsufficientMaterial", &rules.InsufficientMaterial},
+		{"FlyingKings", &rules.FlyingKings},
+		{"BackwardCaptures", &rules.BackwardCaptures},
+		{"Crazyhouse", &rules.Crazyhouse},
+	}
+}
+
+// names of the rules set to on that are the other way by default
+func getChangedRulesString(rules types.RuleSet, on bool) string {
+	var result []string
+
+	defaults := types.DefaultRuleSet
+	defaultFields := getRuleFields(&defaults)
+	for i, field := range getRuleFields(&rules) {
+		if *field.on == on && *defaultFields[i].on != on {
+			result = append(result, field.name)
+		}
+	}
+
+	return strings.Join(result, ",")
+}
+
+func parseChangedRulesString(changedString string, on bool, rules *types.RuleSet) error {
+	if changedString == "" {
+		return nil
+	}
+
+	fields := getRuleFields(rules)
+	for _, name := range strings.Split(changedString, ",") {
+		name = strings.TrimSpace(name)
+		found := false
+		for _, field := range fields {
+			if field.name == name {
+				*field.on = on
+				found = true
+			}
+		}
+
+		if !found {
+			return fmt.Errorf("Invalid rule %s", name)
+		}
+	}
+
+	return nil
+}
+
+var forcedCaptureNames = map[int]string{
+	types.CheckerForcedCapture: "Checkers",
+	types.AllForcedCapture:     "All",
+}
+
+func parseForcedCaptureString(forcedCaptureString string) (int, error) {
+	if forcedCaptureString == "" {
+		return types.NoForcedCapture, nil
+	}
+
+	for rule, name := range forcedCaptureNames {
+		if name == forcedCaptureString {
+			return rule, nil
+		}
+	}
+
+	return types.NoForcedCapture, fmt.Errorf("Invalid ForcedCapture %s", forcedCaptureString)
+}
+
+var repetitionRuleNames = map[int]string{
+	types.ChessRepetition:      "Chess",
+	types.SennichiteRepetition: "Sennichite",
+}
+
+func parseRepetitionRuleString(repetitionRuleString string) (int, error) {
+	if repetitionRuleString == "" {
+		return types.AutoRepetition, nil
+	}
+
+	for rule, name := range repetitionRuleNames {
+		if name == repetitionRuleString {
+			return rule, nil
+		}
+	}
+
+	return types.AutoRepetition, fmt.Errorf("Invalid RepetitionRule %s", repetitionRuleString)
+}
@@ -0,0 +1,192 @@
+package engine
+
+import (
+	"encoding/json"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/KainoaGardner/csc/internal/types"
+)
+
+func TestRuleSetMoves(t *testing.T) {
+	cases := []struct {
+		name  string
+		fen   string
+		moves []string //played before checking
+		move  string
+		legal bool //with the rule on
+		off   func(rules *types.RuleSet)
+	}{
+		{"nifu", "4sk*/5/5/2SP*2/SK*4 1/0/0/0/0/0/0/0/0/0/0/0/0/0 w - - 0 0 600000/600000", nil, "P*,c4", false, func(rules *types.RuleSet) { rules.Nifu = false }},
+		{"uchifuzume", "sk*sl*3/1sl*3/SC*4/5/4SK* 1/0/0/0/1/0/0/0/0/0/0/0/0/0 w - - 0 0 600000/600000", nil, "P*,a4", false, func(rules *types.RuleSet) { rules.Uchifuzume = false }},
+		{"en passant", "4ck*3/3cp*4/8/4CP-3/8/8/8/4CK*3 0/0/0/0/0/0/0/0/0/0/0/0/0/0 b - - 0 0 600000/600000", []string{"d7,d5"}, "e5,d6", true, func(rules *types.RuleSet) { rules.EnPassant = false }},
+		{"castling", "cr*3ck*3/8/8/8/8/8/8/4CK*2CR* 0/0/0/0/0/0/0/0/0/0/0/0/0/0 w - - 0 0 600000/600000", nil, "e1,h1", true, func(rules *types.RuleSet) { rules.Castling = false }},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			rules := types.DefaultRuleSet
+			c.off(&rules)
+
+			for _, on := range []bool{true, false} {
+				game := loadTestGame(t, c.fen)
+				if !on {
+					game.Rules = &rules
+				}
+
+				for _, moveString := range c.moves {
+					if err := playMove(game, moveString); err != nil {
+						t.Fatal(err)
+					}
+				}
+
+				legal := slices.Contains(legalMoveStrings(t, *game), c.move)
+				expected := c.legal == on
+				if legal != expected {
+					t.Errorf("rule on %v: %s legal = %v, expected %v", on, c.move, legal, expected)
+				}
+			}
+		})
+	}
+}
+
+func TestRuleSetDraws(t *testing.T) {
+	kingShuffle := []string{"e1,f1", "e8,f8", "f1,e1", "f8,e8"}
+
+	cases := []struct {
+		name   string
+		fen    string
+		moves  []string
+		reason string
+		off    func(rules *types.RuleSet)
+	}{
+		{"repetition", "cr*3ck*3/8/8/8/8/8/8/CR*3CK*3 0/0/0/0/0/0/0/0/0/0/0/0/0/0 w - - 0 0 600000/600000", slices.Concat(kingShuffle, kingShuffle, kingShuffle), "Repitition", func(rules *types.RuleSet) { rules.Repetition = false }},
+		{"fifty move", "cr*3ck*3/8/8/8/8/8/8/CR*3CK*3 0/0/0/0/0/0/0/0/0/0/0/0/0/0 w - - 99 0 600000/600000", []string{"e1,f1"}, "Fifty Move Rule", func(rules *types.RuleSet) { rules.FiftyMove = false }},
+		{"insufficient material", "4ck*3/8/8/8/8/8/3cq*4/4CK*3 0/0/0/0/0/0/0/0/0/0/0/0/0/0 w - - 0 0 600000/600000", []string{"e1,d2"}, "Insufficient Material", func(rules *types.RuleSet) { rules.InsufficientMaterial = false }},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			rules := types.DefaultRuleSet
+			c.off(&rules)
+
+			for _, on := range []bool{true, false} {
+				game := loadTestGame(t, c.fen)
+				if !on {
+					game.Rules = &rules
+				}
+
+				for _, moveString := range c.moves {
+					if game.State == types.OverState {
+						break
+					}
+					if err := playMove(game, moveString); err != nil {
+						t.Fatalf("%s: %v", moveString, err)
+					}
+				}
+
+				over := game.State == types.OverState && game.Reason == c.reason
+				if over != on {
+					t.Errorf("rule on %v: got state %d reason %q", on, game.State, game.Reason)
+				}
+			}
+		})
+	}
+}
+
+func TestRuleSetSetup(t *testing.T) {
+	postGame := types.PostGame{
+		Width:     8,
+		Height:    8,
+		PlaceLine: 4,
+		Money:     [2]int{100, 100},
+	}
+	game, err := SetupNewGame(postGame, "white")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if GetRules(*game) != types.DefaultRuleSet {
+		t.Errorf("games should play every rule by default, got %+v", GetRules(*game))
+	}
+
+	castling := false
+	postGame.Rules = &types.PostRuleSet{Castling: &castling}
+	game, err = SetupNewGame(postGame, "white")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	castling = true
+	if GetRules(*game).Castling || !GetRules(*game).Nifu {
+		t.Errorf("game should keep its own copy of the rules, got %+v", GetRules(*game))
+	}
+}
+
+func TestRuleSetPartialPost(t *testing.T) {
+	var postGame types.PostGame
+	err := json.Unmarshal([]byte(`{"width":8,"height":8,"placeLine":4,"money":[100,100],"rules":{"flyingKings":true,"castling":false}}`), &postGame)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	game, err := SetupNewGame(postGame, "white")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := types.DefaultRuleSet
+	expected.FlyingKings = true
+	expected.Castling = false
+	if GetRules(*game) != expected {
+		t.Errorf("rules left out should keep their default, got %+v", GetRules(*game))
+	}
+}
+
+func TestRuleSetGameLog(t *testing.T) {
+	startFEN := "4ck*3/8/8/8/8/8/3cq*4/4CK*3 0/0/0/0/0/0/0/0/0/0/0/0/0/0 w - - 0 0 600000/600000"
+
+	rules := types.DefaultRuleSet
+	rules.InsufficientMaterial = false
+	rules.EnPassant = false
+	rules.ForcedCapture = types.CheckerForcedCapture
+	rules.FlyingKings = true
+	rules.RepetitionRule = types.SennichiteRepetition
+
+	game := loadTestGame(t, startFEN)
+	game.Rules = &rules
+	game.Board.PlaceLine = 4
+	gameLog := SetupGameLog(*game, startFEN)
+	for _, moveString := range []string{"e1,d2", "e8,d8", "d2,e2"} {
+		if err := playMove(game, moveString); err != nil {
+			t.Fatal(err)
+		}
+		fen, err := ConvertBoardToString(*game)
+		if err != nil {
+			t.Fatal(err)
+		}
+		gameLog.Moves = append(gameLog.Moves, moveString)
+		gameLog.BoardStates = append(gameLog.BoardStates, fen)
+	}
+
+	record, err := ExportGameLog(*gameLog, "alice", "bob")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(record, `[DisabledRules "EnPassant,InsufficientMaterial"]`) || !strings.Contains(record, `[EnabledRules "FlyingKings"]`) || !strings.Contains(record, `[ForcedCapture "Checkers"]`) || !strings.Contains(record, `[RepetitionRule "Sennichite"]`) {
+		t.Errorf("export should list the rules:\n%s", record)
+	}
+
+	imported, err := ImportGameLog(record)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if imported.Rules == nil || *imported.Rules != rules || len(imported.Moves) != 3 {
+		t.Errorf("got rules %+v and %d moves", imported.Rules, len(imported.Moves))
+	}
+
+	_, err = ImportGameLog(strings.Replace(record, "EnPassant,", "Castles,", 1))
+	if err == nil {
+		t.Errorf("unknown rule names should not import")
+	}
+}
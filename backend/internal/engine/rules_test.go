@@ -0,0 +1,175 @@
+package engine
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/KainoaGardner/csc/internal/types"
+)
+
+func loadTestGame(t *testing.T, fen string) *types.Game {
+	t.Helper()
+
+	game, err := ConvertStringToBoard(fen)
+	if err != nil {
+		t.Fatalf("could not load %q: %v", fen, err)
+	}
+
+	return game
+}
+
+func legalMoveStrings(t *testing.T, game types.Game) []string {
+	t.Helper()
+
+	moves, err := LegalMoveStrings(game)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return moves
+}
+
+func playMove(game *types.Game, moveString string) error {
+	move, err := ConvertStringToMove(moveString, *game)
+	if err != nil {
+		return err
+	}
+
+	game.LastMoveTime = time.Now().UTC()
+	return MovePiece(move, game)
+}
+
+func TestNifu(t *testing.T) {
+	game := loadTestGame(t, "4sk*/5/5/2SP*2/SK*4 1/0/0/0/0/0/0/0/0/0/0/0/0/0 w - - 0 0 600000/600000")
+
+	moves := legalMoveStrings(t, *game)
+	if slices.Contains(moves, "P*,c4") {
+		t.Errorf("nifu drop P*,c4 should not be legal")
+	}
+	if !slices.Contains(moves, "P*,b4") {
+		t.Errorf("drop P*,b4 should be legal")
+	}
+
+	if err := playMove(game, "P*,c4"); err == nil {
+		t.Errorf("MovePiece allowed a nifu drop")
+	}
+}
+
+func TestUchifuzume(t *testing.T) {
+	fen := "sk*sl*3/1sl*3/SC*4/5/4SK* 1/0/0/0/1/0/0/0/0/0/0/0/0/0 w - - 0 0 600000/600000"
+	game := loadTestGame(t, fen)
+
+	moves := legalMoveStrings(t, *game)
+	if slices.Contains(moves, "P*,a4") {
+		t.Errorf("mating fu drop P*,a4 should not be legal")
+	}
+	if !slices.Contains(moves, "G*,a4") {
+		t.Errorf("mating kin drop G*,a4 should be legal")
+	}
+
+	if err := playMove(game, "G*,a4"); err != nil {
+		t.Fatal(err)
+	}
+	if game.Reason != "Checkmate" || game.Winner == nil || *game.Winner != types.White {
+		t.Errorf("expected white checkmate, got winner %v reason %q", game.Winner, game.Reason)
+	}
+}
+
+func TestEnPassant(t *testing.T) {
+	game := loadTestGame(t, "4ck*3/3cp*4/8/4CP-3/8/8/8/4CK*3 0/0/0/0/0/0/0/0/0/0/0/0/0/0 b - - 0 0 600000/600000")
+
+	if err := playMove(game, "d7,d5"); err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Contains(legalMoveStrings(t, *game), "e5,d6") {
+		t.Fatalf("en passant e5,d6 should be legal")
+	}
+
+	if err := playMove(game, "e5,d6"); err != nil {
+		t.Fatal(err)
+	}
+	if game.Board.Board[3][3] != nil {
+		t.Errorf("en passant did not remove the captured pawn")
+	}
+	piece := game.Board.Board[2][3]
+	if piece == nil || piece.Type != types.Pawn || piece.Owner != types.White {
+		t.Errorf("capturing pawn not on d6")
+	}
+}
+
+func TestEnPassantExpires(t *testing.T) {
+	game := loadTestGame(t, "4ck*3/3cp*4/8/4CP-3/8/8/8/4CK*3 0/0/0/0/0/0/0/0/0/0/0/0/0/0 b - - 0 0 600000/600000")
+
+	for _, moveString := range []string{"d7,d5", "e1,f1", "e8,f8"} {
+		if err := playMove(game, moveString); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if slices.Contains(legalMoveStrings(t, *game), "e5,d6") {
+		t.Errorf("en passant should only be available right after the double step")
+	}
+}
+
+func TestCastling(t *testing.T) {
+	cases := []struct {
+		name  string
+		fen   string
+		legal bool
+	}{
+		{"clear", "cr*3ck*3/8/8/8/8/8/8/4CK*2CR* 0/0/0/0/0/0/0/0/0/0/0/0/0/0 w - - 0 0 600000/600000", true},
+		{"through check", "4ck*cr*2/8/8/8/8/8/8/4CK*2CR* 0/0/0/0/0/0/0/0/0/0/0/0/0/0 w - - 0 0 600000/600000", false},
+		{"into check", "4ck*1cr*1/8/8/8/8/8/8/4CK*2CR* 0/0/0/0/0/0/0/0/0/0/0/0/0/0 w - - 0 0 600000/600000", false},
+		{"out of check", "3ck*4/8/8/8/4cr*3/8/8/4CK*2CR* 0/0/0/0/0/0/0/0/0/0/0/0/0/0 w - - 0 0 600000/600000", false},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			game := loadTestGame(t, c.fen)
+			legal := slices.Contains(legalMoveStrings(t, *game), "e1,h1")
+			if legal != c.legal {
+				t.Errorf("castle e1,h1 legal = %v, expected %v", legal, c.legal)
+			}
+		})
+	}
+
+	game := loadTestGame(t, cases[0].fen)
+	if err := playMove(game, "e1,h1"); err != nil {
+		t.Fatal(err)
+	}
+	king := game.Board.Board[7][6]
+	rook := game.Board.Board[7][5]
+	if king == nil || king.Type != types.King || rook == nil || rook.Type != types.Rook {
+		t.Errorf("castle should leave king on g1 and rook on f1")
+	}
+}
+
+func TestCheckerMultiJump(t *testing.T) {
+	game := loadTestGame(t, "7ck*/8/8/8/3kc*4/8/1kc*6/KC*6CK* 0/0/0/0/0/0/0/0/0/0/0/0/0/0 w - - 0 0 600000/600000")
+
+	if err := playMove(game, "a1,c3"); err != nil {
+		t.Fatal(err)
+	}
+	if game.Turn != types.White || game.CheckerJump == nil {
+		t.Fatalf("white should have to continue jumping")
+	}
+
+	moves := legalMoveStrings(t, *game)
+	if !slices.Equal(moves, []string{"c3,e5"}) {
+		t.Errorf("only the continuation jump should be legal, got %v", moves)
+	}
+	if err := playMove(game, "h1,g1"); err == nil {
+		t.Errorf("MovePiece allowed a move during a checker jump")
+	}
+
+	if err := playMove(game, "c3,e5"); err != nil {
+		t.Fatal(err)
+	}
+	if game.Turn != types.Black || game.CheckerJump != nil {
+		t.Errorf("turn should pass to black after the last jump")
+	}
+	if game.Board.Board[6][1] != nil || game.Board.Board[4][3] != nil {
+		t.Errorf("both jumped checkers should be removed")
+	}
+}
@@ -0,0 +1,145 @@
+package engine
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/KainoaGardner/csc/internal/types"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func playUntilOver(t *testing.T, game *types.Game, moves []string) int {
+	t.Helper()
+
+	for i, moveString := range moves {
+		if game.State == types.OverState {
+			return i
+		}
+		if err := playMove(game, moveString); err != nil {
+			t.Fatalf("%s: %v", moveString, err)
+		}
+	}
+
+	return len(moves)
+}
+
+func TestSennichite(t *testing.T) {
+	fen := "4sk-3/8/8/8/8/8/8/4SK-3 0/0/0/0/0/0/0/0/0/0/0/0/0/0 w - - 0 0 600000/600000"
+	kingShuffle := []string{"e1,f1", "e8,f8", "f1,e1", "f8,e8"}
+
+	game := loadTestGame(t, fen)
+	playUntilOver(t, game, slices.Concat(kingShuffle, kingShuffle, kingShuffle))
+	if game.State == types.OverState {
+		t.Fatalf("shogi positions should not end on the third repetition, got %q", game.Reason)
+	}
+
+	playUntilOver(t, game, kingShuffle)
+	if game.State != types.OverState || game.Reason != "Sennichite" || *game.Winner != types.Tie {
+		t.Errorf("fourth repetition should be sennichite, got state %d reason %q", game.State, game.Reason)
+	}
+
+	game = loadTestRulesGame(t, fen, func(rules *types.RuleSet) { rules.RepetitionRule = types.ChessRepetition })
+	playUntilOver(t, game, slices.Concat(kingShuffle, kingShuffle, kingShuffle))
+	if game.State != types.OverState || game.Reason != "Repitition" {
+		t.Errorf("chess repetition should end on the third repetition, got state %d reason %q", game.State, game.Reason)
+	}
+}
+
+func TestPerpetualCheck(t *testing.T) {
+	checks := []string{"a8,b8", "a1,b1", "b8,a8", "b1,a1"}
+
+	cases := []struct {
+		name   string
+		fen    string
+		moves  []string
+		played int
+	}{
+		//the position repeating a fourth time has the checked side to move
+		{"checked side to move", "ck-7/8/8/8/8/8/8/2CR-4CK- 0/0/0/0/0/0/0/0/0/0/0/0/0/0 w - - 0 0 600000/600000", slices.Concat([]string{"c1,a1"}, checks, checks, checks), 13},
+		//the position repeating a fourth time has the checking side to move
+		{"checking side to move", "ck-7/8/8/8/8/8/8/CR-6CK- 0/0/0/0/0/0/0/0/0/0/0/0/0/0 b - - 0 0 600000/600000", slices.Concat(checks, checks, checks, checks), 13},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			game := loadTestRulesGame(t, c.fen, func(rules *types.RuleSet) { rules.RepetitionRule = types.SennichiteRepetition })
+			played := playUntilOver(t, game, c.moves)
+			if played != c.played || game.State != types.OverState {
+				t.Fatalf("game should end after %d moves, played %d with state %d", c.played, played, game.State)
+			}
+			if game.Reason != "Perpetual Check" || *game.Winner != types.Black {
+				t.Errorf("side giving perpetual check should lose, got winner %d reason %q", *game.Winner, game.Reason)
+			}
+		})
+	}
+}
+
+func TestPerpetualCheckTakeback(t *testing.T) {
+	fen := "ck-7/8/8/8/8/8/8/2CR-4CK- 0/0/0/0/0/0/0/0/0/0/0/0/0/0 w - - 0 0 600000/600000"
+	game := loadTestRulesGame(t, fen, func(rules *types.RuleSet) { rules.RepetitionRule = types.SennichiteRepetition })
+	playUntilOver(t, game, []string{"c1,a1", "a8,b8", "a1,b1", "b8,a8", "b1,a1"})
+
+	key, err := ConvertBoardToStringPositionKey(*game)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if record := game.PositionHistory[key]; record.Count != 2 || record.First != 1 {
+		t.Errorf("got position record %+v, expected count 2 first 1", record)
+	}
+
+	if _, err := TakebackRequest(true, types.White, game); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := TakebackRequest(true, types.Black, game); err != nil {
+		t.Fatal(err)
+	}
+	if record := game.PositionHistory[key]; record.Count != 1 || record.First != 1 {
+		t.Errorf("takeback should keep the first occurrence, got %+v", record)
+	}
+}
+
+func TestRepetitionRuleConfig(t *testing.T) {
+	repetitionRule := 5
+
+	postGame := types.PostGame{
+		Width:     8,
+		Height:    8,
+		PlaceLine: 4,
+		Money:     [2]int{100, 100},
+		Rules:     &types.PostRuleSet{RepetitionRule: &repetitionRule},
+	}
+	_, err := SetupNewGame(postGame, "white")
+	if err == nil {
+		t.Errorf("repetition rule 5 should not exist")
+	}
+}
+
+func TestPositionHistoryDecode(t *testing.T) {
+	legacy, err := bson.Marshal(bson.M{"positionHistory": bson.M{"a": 3, "b": int64(2)}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var game types.Game
+	err = bson.Unmarshal(legacy, &game)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if game.PositionHistory["a"] != (types.PositionRecord{Count: 3}) || game.PositionHistory["b"] != (types.PositionRecord{Count: 2}) {
+		t.Errorf("games saved with plain counts should still load, got %+v", game.PositionHistory)
+	}
+
+	game.PositionHistory["c"] = types.PositionRecord{Count: 2, First: 5}
+	data, err := bson.Marshal(game)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var result types.Game
+	err = bson.Unmarshal(data, &result)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.PositionHistory["c"] != game.PositionHistory["c"] {
+		t.Errorf("got %+v, expected %+v", result.PositionHistory["c"], game.PositionHistory["c"])
+	}
+}
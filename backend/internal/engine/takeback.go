@@ -0,0 +1,134 @@
+package engine
+
+import (
+	"fmt"
+	"github.com/KainoaGardner/csc/internal/types"
+	"strings"
+	"time"
+)
+
+func TakebackRequest(takeback bool, turn int, game *types.Game) (int, error) {
+	err := checkGameState(types.MoveState, game.State)
+	if err != nil {
+		return 0, err
+	}
+
+	if !takeback {
+		game.Takeback[0] = false
+		game.Takeback[1] = false
+		return 0, nil
+	}
+
+	otherTurn := getOtherTurn(turn)
+	requester := turn
+	if game.Takeback[otherTurn] {
+		requester = otherTurn
+	}
+
+	count := getTakebackCount(*game, requester)
+	if count == 0 {
+		return 0, fmt.Errorf("No moves to take back")
+	}
+
+	game.Takeback[turn] = true
+	if !(game.Takeback[0] && game.Takeback[1]) {
+		return 0, nil
+	}
+
+	err = rollbackGame(game, count)
+	if err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// number of states to pop to get back to the start of turn's last move
+func getTakebackCount(game types.Game, turn int) int {
+	count := 0
+	for i := len(game.StateHistory) - 1; i >= 0; i-- {
+		count++
+
+		fields := strings.Fields(game.StateHistory[i])
+		if len(fields) < 5 {
+			return 0
+		}
+
+		stateTurn, err := getTurnFromString(fields[2])
+		if err != nil {
+			return 0
+		}
+
+		if stateTurn == turn && fields[4] == "-" {
+			return count
+		}
+	}
+
+	return 0
+}
+
+func rollbackGame(game *types.Game, count int) error {
+	for i := 0; i < count; i++ {
+		if game.CheckerJump == nil {
+			boardString, err := ConvertBoardToStringPositionKey(*game)
+			if err != nil {
+				return err
+			}
+			removeMoveHistory(boardString, game)
+		}
+
+		last := len(game.StateHistory) - 1
+		err := restoreGameState(game.StateHistory[last], game)
+		if err != nil {
+			return err
+		}
+
+		//games saved before periods were kept only get their fen back
+		if len(game.PeriodHistory) == len(game.StateHistory) {
+			game.Periods = game.PeriodHistory[last]
+			game.PeriodHistory = game.PeriodHistory[:last]
+		}
+		game.StateHistory = game.StateHistory[:last]
+	}
+
+	game.Takeback[0] = false
+	game.Takeback[1] = false
+	game.LastMoveTime = time.Now().UTC()
+	armClock(*game)
+
+	return nil
+}
+
+func restoreGameState(fen string, game *types.Game) error {
+	state, err := ConvertStringToBoard(fen)
+	if err != nil {
+		return err
+	}
+
+	if state.Board.Width != game.Board.Width || state.Board.Height != game.Board.Height {
+		return fmt.Errorf("Saved state does not match board size")
+	}
+
+	game.Board.Board = state.Board.Board
+	game.Mochigoma = state.Mochigoma
+	game.Hand = state.Hand
+	game.Turn = state.Turn
+	game.EnPassant = state.EnPassant
+	game.CheckerJump = state.CheckerJump
+	game.HalfMoveCount = state.HalfMoveCount
+	game.MoveCount = state.MoveCount
+	game.Time = state.Time
+
+	return nil
+}
+
+func updateStateHistory(game *types.Game) error {
+	fen, err := ConvertBoardToString(*game)
+	if err != nil {
+		return err
+	}
+
+	game.StateHistory = append(game.StateHistory, fen)
+	game.PeriodHistory = append(game.PeriodHistory, game.Periods)
+	return nil
+}
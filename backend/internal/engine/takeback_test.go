@@ -0,0 +1,130 @@
+package engine
+
+import (
+	"testing"
+	"time"
+
+	"github.com/KainoaGardner/csc/internal/types"
+)
+
+func TestTakeback(t *testing.T) {
+	fen := "4ck*3/4cp*3/8/8/8/8/4CP*3/4CK*3 0/0/0/0/0/0/0/0/0/0/0/0/0/0 w - - 0 0 600000/600000"
+	game := loadTestGame(t, fen)
+
+	if _, err := TakebackRequest(true, types.White, game); err == nil {
+		t.Errorf("takeback should fail before any moves")
+	}
+
+	for _, moveString := range []string{"e2,e4", "e7,e5"} {
+		if err := playMove(game, moveString); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	count, err := TakebackRequest(true, types.White, game)
+	if err != nil || count != 0 {
+		t.Fatalf("request should wait for the opponent, got count %d err %v", count, err)
+	}
+
+	count, err = TakebackRequest(true, types.Black, game)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != 2 {
+		t.Errorf("expected both moves to be taken back, got %d", count)
+	}
+
+	result, err := ConvertBoardToString(*game)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result != fen {
+		t.Errorf("got %q after takeback, expected %q", result, fen)
+	}
+	if len(game.PositionHistory) != 0 || len(game.StateHistory) != 0 {
+		t.Errorf("position and state history should be rolled back")
+	}
+	if game.Takeback != [2]bool{false, false} {
+		t.Errorf("takeback flags should reset")
+	}
+}
+
+func TestTakebackCheckerJump(t *testing.T) {
+	fen := "kc*6ck*/8/8/8/3kc*4/8/CP*kc*6/KC*6CK* 0/0/0/0/0/0/0/0/0/0/0/0/0/0 w - - 0 0 600000/600000"
+	game := loadTestGame(t, fen)
+
+	for _, moveString := range []string{"a1,c3", "c3,e5"} {
+		if err := playMove(game, moveString); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if _, err := TakebackRequest(true, types.White, game); err != nil {
+		t.Fatal(err)
+	}
+	if err := playMove(game, "h8,g8"); err != nil {
+		t.Fatal(err)
+	}
+	if game.Takeback != [2]bool{false, false} {
+		t.Errorf("a move should cancel a pending takeback")
+	}
+
+	if _, err := TakebackRequest(true, types.Black, game); err != nil {
+		t.Fatal(err)
+	}
+	count, err := TakebackRequest(true, types.White, game)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != 1 {
+		t.Errorf("expected only black's move to be taken back, got %d", count)
+	}
+
+	if _, err := TakebackRequest(true, types.White, game); err != nil {
+		t.Fatal(err)
+	}
+	count, err = TakebackRequest(true, types.Black, game)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != 2 {
+		t.Errorf("expected the whole multi jump to be taken back, got %d", count)
+	}
+
+	result, err := ConvertBoardToString(*game)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result != fen {
+		t.Errorf("got %q after takeback, expected %q", result, fen)
+	}
+}
+
+func TestTakebackByoyomi(t *testing.T) {
+	fen := "4ck*3/4cp*3/8/8/8/8/4CP*3/4CK*3 0/0/0/0/0/0/0/0/0/0/0/0/0/0 w - - 0 0 0/0"
+	game := loadTestGame(t, fen)
+	game.TimeControl = types.TimeControl{Type: types.Byoyomi, Periods: 3, PeriodTime: 10000}
+	game.Periods = [2]int{3, 3}
+
+	move, err := ConvertStringToMove("e2,e4", *game)
+	if err != nil {
+		t.Fatal(err)
+	}
+	game.LastMoveTime = time.Now().UTC().Add(-(25*time.Second + moveTimeBuffer))
+	if err := MovePiece(move, game); err != nil {
+		t.Fatal(err)
+	}
+	if game.Periods[types.White] != 1 {
+		t.Fatalf("move should use two periods, got %v", game.Periods)
+	}
+
+	if _, err := TakebackRequest(true, types.White, game); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := TakebackRequest(true, types.Black, game); err != nil {
+		t.Fatal(err)
+	}
+	if game.Periods != [2]int{3, 3} || len(game.PeriodHistory) != 0 {
+		t.Errorf("takeback should give the periods back, got %v", game.Periods)
+	}
+}
@@ -1,86 +1,172 @@
 package engine
 
 import (
-	"time"
-
 	"fmt"
-	"github.com/KainoaGardner/csc/internal/config"
-	"github.com/KainoaGardner/csc/internal/db"
 	"github.com/KainoaGardner/csc/internal/types"
-	"go.mongodb.org/mongo-driver/mongo"
-	"log"
+	"time"
 )
 
-func checkTimeLoss(game types.Game) bool {
-	if game.Time[game.Turn] < 0 {
-		return true
+// grace given to each move for network latency before the clock runs
+const moveTimeBuffer = 2 * time.Second
+
+func setupTimeControl(config types.PostTimeControl) types.TimeControl {
+	var result types.TimeControl
+
+	result.Type = config.Type
+	result.Increment = config.Increment * 1000
+	result.Periods = config.Periods
+	result.PeriodTime = config.PeriodTime * 1000
+
+	return result
+}
+
+func checkTimeControlConfig(config types.PostTimeControl) error {
+	if config.Increment < 0 || config.Periods < 0 || config.PeriodTime < 0 {
+		return fmt.Errorf("Cannot have negative time control values")
 	}
 
-	return false
+	if config.Increment > 3600 || config.PeriodTime > 3600 {
+		return fmt.Errorf("Increment and period time limit 3600 seconds")
+	}
+
+	if config.Periods > 100 {
+		return fmt.Errorf("Byoyomi period limit 100")
+	}
+
+	switch config.Type {
+	case types.SuddenDeath:
+		return nil
+	case types.FischerIncrement, types.BronsteinDelay, types.SimpleDelay:
+		if config.Increment == 0 {
+			return fmt.Errorf("Increment or delay must be positive")
+		}
+	case types.Byoyomi:
+		if config.Periods == 0 || config.PeriodTime == 0 {
+			return fmt.Errorf("Byoyomi needs at least one period with positive period time")
+		}
+	default:
+		return fmt.Errorf("Invalid time control type")
+	}
+
+	return nil
 }
 
-func updateMoveTime(game *types.Game) {
-	currTime := time.Now().UTC()
+func getElapsedMoveTime(game types.Game, currTime time.Time) int64 {
 	dt := currTime.Sub(game.LastMoveTime).Milliseconds()
-	buffer := (time.Duration(2) * time.Second).Milliseconds()
+	buffer := moveTimeBuffer.Milliseconds()
 
 	if dt <= buffer {
-		return
+		return 0
+	}
+	return dt - buffer
+}
+
+// time the side to move can spend after the buffer before flagging
+func getTimeAllowance(game types.Game) int64 {
+	timeControl := game.TimeControl
+	remaining := game.Time[game.Turn]
+
+	switch timeControl.Type {
+	case types.SimpleDelay:
+		return remaining + timeControl.Increment
+	case types.Byoyomi:
+		return remaining + int64(game.Periods[game.Turn])*timeControl.PeriodTime
+	default:
+		return remaining
+	}
+}
+
+// returns true if the side to move ran out of time
+func updateMoveTime(game *types.Game) bool {
+	currTime := time.Now().UTC()
+	elapsed := getElapsedMoveTime(*game, currTime)
+	game.LastMoveTime = currTime
+
+	if elapsed > getTimeAllowance(*game) {
+		return true
 	}
-	game.Time[game.Turn] -= dt - buffer
-	game.LastMoveTime = time.Now().UTC()
+
+	applyTimeControl(game, elapsed)
+	return false
 }
 
-func StartGlobalTimeCheck(
-	interval time.Duration,
-	client *mongo.Client,
-	config config.Config,
-	gameOver func(*types.Game, string, string, *mongo.Client, config.Config) bool,
-) {
-	ticker := time.NewTicker(interval)
-
-	go func() {
-		for range ticker.C {
-
-			games, err := db.ListAllGames(client, config.DB)
-			if err != nil {
-				log.Println(err)
-				continue
-			}
-
-			currTime := time.Now().UTC()
-			for _, game := range games {
-				if game.State != types.MoveState {
-					continue
-				}
-
-				dt := currTime.Sub(game.LastMoveTime).Milliseconds()
-				buffer := (time.Duration(2) * time.Second).Milliseconds()
-
-				if dt <= buffer {
-					continue
-				}
-
-				remainingTime := game.Time[game.Turn] - dt + buffer
-				if remainingTime < 0 {
-					playerID := ""
-					if game.Turn == types.White {
-						playerID = game.WhiteID
-					} else {
-						playerID = game.BlackID
-					}
-
-					moveTurn := getEnemyTurnInt(*game)
-					game.Winner = &moveTurn
-					game.Reason = "Time"
-					game.State = types.OverState
-
-					game.Time[game.Turn] = 0
-					gameOver(game, game.ID.Hex(), playerID, client, config)
-					fmt.Println("TIme up")
-				}
-			}
+func applyTimeControl(game *types.Game, elapsed int64) {
+	timeControl := game.TimeControl
+	turn := game.Turn
+
+	switch timeControl.Type {
+	case types.FischerIncrement:
+		game.Time[turn] += timeControl.Increment - elapsed
+	case types.BronsteinDelay:
+		//time used is given back up to the delay
+		game.Time[turn] -= elapsed - min(elapsed, timeControl.Increment)
+	case types.SimpleDelay:
+		//clock only starts after the delay
+		game.Time[turn] -= max(elapsed-timeControl.Increment, 0)
+	case types.Byoyomi:
+		overflow := elapsed - game.Time[turn]
+		if overflow <= 0 {
+			game.Time[turn] -= elapsed
+			return
 		}
-	}()
 
+		//a period is only used up when it runs out completely
+		game.Time[turn] = 0
+		game.Periods[turn] -= int((overflow - 1) / timeControl.PeriodTime)
+	default:
+		game.Time[turn] -= elapsed
+	}
+}
+
+// remaining time as of now, the stored time only changes when a move is made
+func GetClock(game types.Game) types.ClockResponse {
+	var result types.ClockResponse
+
+	currTime := time.Now().UTC()
+	result.Time = game.Time
+	result.Periods = game.Periods
+	result.ServerTime = currTime.UnixMilli()
+
+	timeControl := game.TimeControl
+	if timeControl.Type == types.Byoyomi {
+		result.Period = timeControl.PeriodTime
+	}
+
+	if game.State != types.MoveState {
+		return result
+	}
+
+	turn := game.Turn
+	result.Running = &turn
+	result.StartsAt = game.LastMoveTime.Add(moveTimeBuffer).UnixMilli()
+
+	elapsed := getElapsedMoveTime(game, currTime)
+	switch timeControl.Type {
+	case types.SimpleDelay:
+		result.Time[turn] -= max(elapsed-timeControl.Increment, 0)
+	case types.Byoyomi:
+		overflow := elapsed - game.Time[turn]
+		if overflow <= 0 {
+			result.Time[turn] -= elapsed
+			break
+		}
+
+		used := (overflow - 1) / timeControl.PeriodTime
+		result.Time[turn] = 0
+		result.Periods[turn] = max(result.Periods[turn]-int(used), 0)
+		result.Period = max(timeControl.PeriodTime*(used+1)-overflow, 0)
+	default:
+		result.Time[turn] -= elapsed
+	}
+	result.Time[turn] = max(result.Time[turn], 0)
+
+	return result
+}
+
+func setupTimeLoss(game *types.Game) {
+	moveTurn := getEnemyTurnInt(*game)
+	game.Winner = &moveTurn
+	game.Reason = "Time"
+	game.State = types.OverState
+	game.Time[game.Turn] = 0
 }
@@ -0,0 +1,134 @@
+package engine
+
+import (
+	"testing"
+	"time"
+
+	"github.com/KainoaGardner/csc/internal/config"
+	"github.com/KainoaGardner/csc/internal/store"
+	"github.com/KainoaGardner/csc/internal/types"
+)
+
+type timeControlTest struct {
+	name        string
+	timeControl types.TimeControl
+	time        int64
+	periods     int
+	elapsed     int64
+	expected    int64
+	periodsLeft int
+	loss        bool
+}
+
+var timeControlTests = []timeControlTest{
+	{"sudden death", types.TimeControl{Type: types.SuddenDeath}, 10000, 0, 4000, 6000, 0, false},
+	{"sudden death flag", types.TimeControl{Type: types.SuddenDeath}, 10000, 0, 11000, 0, 0, true},
+	{"fischer", types.TimeControl{Type: types.FischerIncrement, Increment: 5000}, 10000, 0, 4000, 11000, 0, false},
+	{"fischer flag", types.TimeControl{Type: types.FischerIncrement, Increment: 5000}, 10000, 0, 11000, 0, 0, true},
+	{"bronstein under delay", types.TimeControl{Type: types.BronsteinDelay, Increment: 5000}, 10000, 0, 3000, 10000, 0, false},
+	{"bronstein over delay", types.TimeControl{Type: types.BronsteinDelay, Increment: 5000}, 10000, 0, 8000, 7000, 0, false},
+	{"bronstein flag", types.TimeControl{Type: types.BronsteinDelay, Increment: 5000}, 10000, 0, 11000, 0, 0, true},
+	{"simple delay under delay", types.TimeControl{Type: types.SimpleDelay, Increment: 5000}, 10000, 0, 3000, 10000, 0, false},
+	{"simple delay over main time", types.TimeControl{Type: types.SimpleDelay, Increment: 5000}, 10000, 0, 14000, 1000, 0, false},
+	{"simple delay flag", types.TimeControl{Type: types.SimpleDelay, Increment: 5000}, 10000, 0, 16000, 0, 0, true},
+	{"byoyomi main time", types.TimeControl{Type: types.Byoyomi, Periods: 3, PeriodTime: 10000}, 10000, 3, 4000, 6000, 3, false},
+	{"byoyomi inside first period", types.TimeControl{Type: types.Byoyomi, Periods: 3, PeriodTime: 10000}, 10000, 3, 20000, 0, 3, false},
+	{"byoyomi uses periods", types.TimeControl{Type: types.Byoyomi, Periods: 3, PeriodTime: 10000}, 0, 3, 25000, 0, 1, false},
+	{"byoyomi last period", types.TimeControl{Type: types.Byoyomi, Periods: 3, PeriodTime: 10000}, 0, 1, 9000, 0, 1, false},
+	{"byoyomi flag", types.TimeControl{Type: types.Byoyomi, Periods: 3, PeriodTime: 10000}, 0, 1, 11000, 0, 0, true},
+}
+
+func TestTimeControls(t *testing.T) {
+	for _, test := range timeControlTests {
+		t.Run(test.name, func(t *testing.T) {
+			game := types.Game{}
+			game.TimeControl = test.timeControl
+			game.Time = [2]int64{test.time, test.time}
+			game.Periods = [2]int{test.periods, test.periods}
+
+			elapsed := time.Duration(test.elapsed)*time.Millisecond + moveTimeBuffer
+			game.LastMoveTime = time.Now().UTC().Add(-elapsed)
+
+			loss := updateMoveTime(&game)
+			if loss != test.loss {
+				t.Fatalf("got loss %v, expected %v", loss, test.loss)
+			}
+			if loss {
+				return
+			}
+
+			//allow for the few ms spent running the test
+			if game.Time[types.White] > test.expected || game.Time[types.White] < test.expected-50 {
+				t.Errorf("got %d ms left, expected %d", game.Time[types.White], test.expected)
+			}
+			if game.Periods[types.White] != test.periodsLeft {
+				t.Errorf("got %d periods left, expected %d", game.Periods[types.White], test.periodsLeft)
+			}
+			if game.Time[types.Black] != test.time {
+				t.Errorf("waiting side clock should not change")
+			}
+		})
+	}
+}
+
+func TestGetClock(t *testing.T) {
+	game := types.Game{}
+	game.State = types.MoveState
+	game.Turn = types.Black
+	game.TimeControl = types.TimeControl{Type: types.Byoyomi, Periods: 3, PeriodTime: 10000}
+	game.Time = [2]int64{5000, 2000}
+	game.Periods = [2]int{3, 3}
+	game.LastMoveTime = time.Now().UTC().Add(-(15*time.Second + moveTimeBuffer))
+
+	clock := GetClock(game)
+	if clock.Running == nil || *clock.Running != types.Black {
+		t.Fatalf("black clock should be running")
+	}
+	if clock.Time != [2]int64{5000, 0} {
+		t.Errorf("got time %v, expected black main time used up", clock.Time)
+	}
+	if clock.Periods != [2]int{3, 2} {
+		t.Errorf("got periods %v, expected one black period used", clock.Periods)
+	}
+	if clock.Period > 7000 || clock.Period < 6950 {
+		t.Errorf("got %d ms left in period, expected about 7000", clock.Period)
+	}
+
+	game.State = types.OverState
+	clock = GetClock(game)
+	if clock.Running != nil {
+		t.Errorf("clock should stop once the game is over")
+	}
+}
+
+func TestGetRunningClock(t *testing.T) {
+	gameStore := store.NewMemoryStore()
+	err := StartClockScheduler(gameStore, config.Config{}, func(*types.Game, string, string, store.Store, config.Config) bool { return true })
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { clock = nil })
+
+	gameID := createTestGame(t, gameStore, "4ck*3/4cp*3/8/8/8/8/4CP*3/4CK*3 0/0/0/0/0/0/0/0/0/0/0/0/0/0 w - - 0 0 600000/600000")
+	if _, ok := GetRunningClock(gameID); ok {
+		t.Errorf("clock should not be known before the game is armed")
+	}
+
+	_, _, err = MoveCase(gameID, "white", types.PostMove{Move: "e2,e4"}, gameStore)
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, ok := GetRunningClock(gameID)
+	if !ok || result.Running == nil || *result.Running != types.Black {
+		t.Fatalf("black clock should be running after white moves, got %+v", result)
+	}
+
+	_, err = ResignCase(gameID, "black", gameStore)
+	if err != nil {
+		t.Fatal(err)
+	}
+	CancelClock(gameID)
+	if _, ok := GetRunningClock(gameID); ok {
+		t.Errorf("finished games should not have a running clock")
+	}
+}
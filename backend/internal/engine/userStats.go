@@ -0,0 +1,42 @@
+package engine
+
+import (
+	"github.com/KainoaGardner/csc/internal/rating"
+	"github.com/KainoaGardner/csc/internal/types"
+	"time"
+)
+
+func getStatsRating(userStats types.UserStats) rating.Rating {
+	return rating.SetupRating(userStats.Rating, userStats.RD, userStats.Volatility)
+}
+
+func SetupUserStatsUpdate(userStats types.UserStats, opponentStats types.UserStats, score float64, gameLogID string) types.UpdateUserStats {
+	var result types.UpdateUserStats
+
+	won := 0
+	if score == 1 {
+		won = 1
+	}
+
+	result.GamesPlayed = userStats.GamesPlayed + 1
+	result.GamesWon = userStats.GamesWon + won
+	result.GameLog = gameLogID
+
+	oldRating := getStatsRating(userStats)
+	results := []rating.Result{{Opponent: getStatsRating(opponentStats), Score: score}}
+	newRating := rating.Update(oldRating, results)
+
+	result.Rating = newRating.Rating
+	result.RD = newRating.RD
+	result.Volatility = newRating.Volatility
+
+	result.RatingRecord = types.RatingRecord{
+		GameLog: gameLogID,
+		Date:    time.Now().UTC(),
+		Rating:  newRating.Rating,
+		RD:      newRating.RD,
+		Change:  newRating.Rating - oldRating.Rating,
+	}
+
+	return result
+}
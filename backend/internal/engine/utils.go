@@ -10,6 +10,7 @@ func copyGame(game types.Game) *types.Game {
 	boardCopy := types.Board{}
 	boardCopy.Width = game.Board.Width
 	boardCopy.Height = game.Board.Height
+	boardCopy.PlaceLine = game.Board.PlaceLine
 
 	boardCopy.Board = make([][]*types.Piece, game.Board.Height)
 	for i := range game.Board.Board {
@@ -20,6 +20,10 @@ func checkValidDrop(move types.Move, piece types.Piece, game types.Game) error {
 	if err != nil {
 		return err
 	}
+	err = checkDropInCheck(move, piece, game)
+	if err != nil {
+		return err
+	}
 	err = checkNifu(move, piece, game)
 	if err != nil {
 		return err
@@ -37,13 +41,11 @@ func checkValidDrop(move types.Move, piece types.Piece, game types.Game) error {
 }
 
 func checkHaveDropPiece(move types.Move, game types.Game) error {
-	offset := getMochigomaOffset(game)
-
 	if move.Drop == nil {
 		return fmt.Errorf("Drop not set")
 	}
 
-	komaCount := game.Mochigoma[*move.Drop+offset]
+	komaCount := getDropCount(*move.Drop, game)
 	if komaCount <= 0 {
 		return fmt.Errorf("Not enough mochigoma")
 	}
@@ -67,8 +69,19 @@ func checkPromoteDrop(move types.Move) error {
 	return nil
 }
 
+func checkDropInCheck(move types.Move, piece types.Piece, game types.Game) error {
+	gameCopy := copyGame(game)
+	gameCopy.Board.Board[move.End.Y][move.End.X] = &piece
+
+	if GetInCheck(*gameCopy) {
+		return fmt.Errorf("Cant drop and leave king in check")
+	}
+
+	return nil
+}
+
 func checkNifu(move types.Move, piece types.Piece, game types.Game) error {
-	if piece.Type != types.Fu {
+	if piece.Type != types.Fu || !GetRules(game).Nifu {
 		return nil
 	}
 
@@ -101,13 +114,22 @@ func checkIkidokoronoNaiKoma(move types.Move, piece types.Piece, game types.Game
 		if move.End.Y == row0 || move.End.Y == row1 {
 			return fmt.Errorf("Can drop piece with no move")
 		}
+	} else if piece.Type == types.Checker {
+		if move.End.Y == row0 {
+			return fmt.Errorf("Cant drop checker on its last row")
+		}
+	} else if piece.Type == types.Pawn {
+		backRow := game.Board.Height - 1 - row0
+		if move.End.Y == row0 || move.End.Y == backRow {
+			return fmt.Errorf("Cant drop pawn on the first or last row")
+		}
 	}
 
 	return nil
 }
 
 func checkUtifudume(move types.Move, piece types.Piece, game types.Game) error {
-	if piece.Type != types.Fu {
+	if piece.Type != types.Fu || !GetRules(game).Uchifuzume {
 		return nil
 	}
 
@@ -30,7 +30,9 @@ func getPieceMoves(pos types.Vec2, piece types.Piece, game types.Game, dir int)
 		possibleMoves = getQueenMoves(pos, piece, game)
 	case types.King:
 		possibleMoves = getKingMoves(pos, piece, game)
-		possibleMoves = append(possibleMoves, getCastleMoves(pos, piece, game)...)
+		if GetRules(game).Castling {
+			possibleMoves = append(possibleMoves, getCastleMoves(pos, piece, game)...)
+		}
 	case types.Fu:
 		possibleMoves = getFuMoves(pos, piece, game, dir)
 	case types.Kyou:
@@ -99,9 +101,9 @@ func getPawnMoves(pos types.Vec2, piece types.Piece, game types.Game, direction
 		newPos.Y += pos.Y
 		if checkPositionInbounds(newPos, game) {
 			space := game.Board.Board[newPos.Y][newPos.X]
-			if space != nil && space.Owner == piece.Owner {
+			if space != nil && space.Owner != piece.Owner {
 				validMovePositions = append(validMovePositions, newPos)
-			} else if space == nil && game.EnPassant != nil && utils.CheckVec2Equal(newPos, *game.EnPassant) {
+			} else if space == nil && GetRules(game).EnPassant && game.EnPassant != nil && utils.CheckVec2Equal(newPos, *game.EnPassant) {
 				validMovePositions = append(validMovePositions, newPos)
 			}
 		}
@@ -488,19 +490,45 @@ func getCheckerMoves(pos types.Vec2, piece types.Piece, game types.Game, dir int
 		}
 	}
 
+	//backward jumps never come with a quiet move
+	if GetRules(game).BackwardCaptures {
+		backward := []types.Vec2{
+			{X: -1, Y: dir},
+			{X: 1, Y: dir},
+		}
+		for _, jumpDir := range backward {
+			landPos, ok := getCheckerLandPos(pos, jumpDir, piece, game)
+			if ok {
+				validMovePositions = append(validMovePositions, landPos)
+			}
+		}
+	}
+
 	return validMovePositions
 }
 
+// square past an adjacent enemy piece in jumpDir if it is empty
+func getCheckerLandPos(pos types.Vec2, jumpDir types.Vec2, piece types.Piece, game types.Game) (types.Vec2, bool) {
+	jumpPos := types.Vec2{X: pos.X + jumpDir.X, Y: pos.Y + jumpDir.Y}
+	landPos := types.Vec2{X: pos.X + jumpDir.X*2, Y: pos.Y + jumpDir.Y*2}
+	if !checkPositionInbounds(landPos, game) {
+		return landPos, false
+	}
+
+	jumpSpace := game.Board.Board[jumpPos.Y][jumpPos.X]
+	landSpace := game.Board.Board[landPos.Y][landPos.X]
+	return landPos, landSpace == nil && jumpSpace != nil && jumpSpace.Owner != piece.Owner
+}
+
 func getCheckerKingMoves(pos types.Vec2, piece types.Piece, game types.Game) []types.Vec2 {
 	var validMovePositions []types.Vec2
 
-	directions := []types.Vec2{
-		{X: -1, Y: -1},
-		{X: 1, Y: -1},
-		{X: -1, Y: 1},
-		{X: 1, Y: 1},
+	if GetRules(game).FlyingKings {
+		return getFlyingCheckerKingMoves(pos, piece, game)
 	}
 
+	directions := checkerKingDirections
+
 	inCheckerJump := game.CheckerJump != nil && utils.CheckVec2Equal(pos, *game.CheckerJump)
 	for i := 0; i < len(directions); i++ {
 		dir := directions[i]
@@ -531,6 +559,41 @@ func getCheckerKingMoves(pos types.Vec2, piece types.Piece, game types.Game) []t
 	return validMovePositions
 }
 
+var checkerKingDirections = []types.Vec2{
+	{X: -1, Y: -1},
+	{X: 1, Y: -1},
+	{X: -1, Y: 1},
+	{X: 1, Y: 1},
+}
+
+// slides any distance and captures the first enemy piece on a diagonal landing on any empty square past it
+func getFlyingCheckerKingMoves(pos types.Vec2, piece types.Piece, game types.Game) []types.Vec2 {
+	var validMovePositions []types.Vec2
+
+	inCheckerJump := game.CheckerJump != nil && utils.CheckVec2Equal(pos, *game.CheckerJump)
+	for _, dir := range checkerKingDirections {
+		newPos := types.Vec2{X: pos.X + dir.X, Y: pos.Y + dir.Y}
+		for checkPositionInbounds(newPos, game) && game.Board.Board[newPos.Y][newPos.X] == nil {
+			if !inCheckerJump {
+				validMovePositions = append(validMovePositions, newPos)
+			}
+			newPos = types.Vec2{X: newPos.X + dir.X, Y: newPos.Y + dir.Y}
+		}
+
+		if !checkPositionInbounds(newPos, game) || game.Board.Board[newPos.Y][newPos.X].Owner == piece.Owner {
+			continue
+		}
+
+		landPos := types.Vec2{X: newPos.X + dir.X, Y: newPos.Y + dir.Y}
+		for checkPositionInbounds(landPos, game) && game.Board.Board[landPos.Y][landPos.X] == nil {
+			validMovePositions = append(validMovePositions, landPos)
+			landPos = types.Vec2{X: landPos.X + dir.X, Y: landPos.Y + dir.Y}
+		}
+	}
+
+	return validMovePositions
+}
+
 func checkEndPosInPossibleMoves(possibleMoves []types.Vec2, move types.Move) error {
 	for i := 0; i < len(possibleMoves); i++ {
 		possibleMove := possibleMoves[i]
@@ -578,40 +641,56 @@ func getCastleMoves(pos types.Vec2, piece types.Piece, game types.Game) []types.
 	return validMovePositions
 }
 
-func checkCheckerNextJumps(startPos types.Vec2, endPos types.Vec2, piece types.Piece, game types.Game) bool {
-	if !checkCheckerTake(startPos, endPos) {
-		return false
-	}
+// jumps the checker at pos can make on game, game has the jumped piece already removed
+func getCheckerJumps(pos types.Vec2, piece types.Piece, game types.Game) []types.Vec2 {
+	var result []types.Vec2
 
 	dir := getMoveDirection(game)
 	var possibleMoves []types.Vec2
 	switch piece.Type {
 	case types.Checker:
-		possibleMoves = getCheckerMoves(endPos, piece, game, dir)
+		possibleMoves = getCheckerMoves(pos, piece, game, dir)
 	case types.CheckerKing:
-		possibleMoves = getCheckerKingMoves(endPos, piece, game)
+		possibleMoves = getCheckerKingMoves(pos, piece, game)
 	default:
-		return false
+		return result
 	}
 
-	for i := len(possibleMoves) - 1; i >= 0; i-- {
-		if !checkCheckerTake(endPos, possibleMoves[i]) {
-			possibleMoves = append(possibleMoves[:i], possibleMoves[i+1:]...)
+	for _, movePos := range possibleMoves {
+		if getCheckerTakePos(pos, movePos, game) != nil {
+			result = append(result, movePos)
 		}
 	}
 
-	return len(possibleMoves) > 0
+	return result
+}
+
+func checkCheckerNextJumps(endPos types.Vec2, piece types.Piece, game types.Game) bool {
+	return len(getCheckerJumps(endPos, piece, game)) > 0
 }
 
-func checkCheckerTake(startPos types.Vec2, endPos types.Vec2) bool {
+// the one piece a checker passes over going from startPos to endPos, nil for a quiet move
+func getCheckerTakePos(startPos types.Vec2, endPos types.Vec2, game types.Game) *types.Vec2 {
 	dx := utils.AbsoluteValueInt(startPos.X - endPos.X)
 	dy := utils.AbsoluteValueInt(startPos.Y - endPos.Y)
+	if dx != dy || dx < 2 {
+		return nil
+	}
 
-	if dx == 2 && dy == 2 {
-		return true
+	var result *types.Vec2
+	dir := getCheckerJumpDir(types.Move{Start: startPos, End: endPos})
+	for i := 1; i < dx; i++ {
+		pos := types.Vec2{X: startPos.X + dir.X*i, Y: startPos.Y + dir.Y*i}
+		if game.Board.Board[pos.Y][pos.X] == nil {
+			continue
+		}
+		if result != nil {
+			return nil
+		}
+		result = &pos
 	}
 
-	return false
+	return result
 }
 
 func filterPossibleMoves(startPos types.Vec2, possibleMoves *[]types.Vec2, game types.Game) {
@@ -621,7 +700,7 @@ func filterPossibleMoves(startPos types.Vec2, possibleMoves *[]types.Vec2, game
 		piece := gameCopy.Board.Board[startPos.Y][startPos.X]
 		if piece != nil && piece.Type >= types.Pawn && piece.Type <= types.Ryuu {
 			takePiece := gameCopy.Board.Board[movePos.Y][movePos.X]
-			validCastle := takePiece != nil && piece.Type == types.King && takePiece.Type == types.Rook && takePiece.Owner == piece.Owner
+			validCastle := checkValidCastle(piece, takePiece, *gameCopy)
 			move := types.Move{
 				Start:   startPos,
 				End:     movePos,
@@ -644,7 +723,7 @@ func filterPossibleMoves(startPos types.Vec2, possibleMoves *[]types.Vec2, game
 			} else {
 				dir := getMoveDirection(*gameCopy)
 				if checkEnPassantTake(move, *gameCopy, piece) {
-					game.Board.Board[move.End.Y+dir][move.End.X] = nil
+					gameCopy.Board.Board[move.End.Y+dir][move.End.X] = nil
 				}
 				gameCopy.Board.Board[startPos.Y][startPos.X] = nil
 				gameCopy.Board.Board[movePos.Y][movePos.X] = piece
@@ -660,44 +739,25 @@ func filterPossibleMoves(startPos types.Vec2, possibleMoves *[]types.Vec2, game
 	}
 }
 
+// in check when every way of finishing the jumps leaves the king in check
 func checkerMovesInCheck(startPos types.Vec2, endPos types.Vec2, piece *types.Piece, game types.Game) bool {
-	if !checkCheckerNextJumps(startPos, endPos, *piece, game) {
-		game.Board.Board[startPos.Y][startPos.X] = nil
-		game.Board.Board[endPos.Y][endPos.X] = piece
-		if GetInCheck(game) {
-			return true
-		} else {
-			return false
-		}
-	} else {
-
-		dir := getMoveDirection(game)
-		var possibleMoves []types.Vec2
-		switch piece.Type {
-		case types.Checker:
-			possibleMoves = getCheckerMoves(endPos, *piece, game, dir)
-		case types.CheckerKing:
-			possibleMoves = getCheckerKingMoves(endPos, *piece, game)
-		}
-		for i := len(possibleMoves) - 1; i >= 0; i-- {
-			if !checkCheckerTake(endPos, possibleMoves[i]) {
-				possibleMoves = append(possibleMoves[:i], possibleMoves[i+1:]...)
-			}
-		}
+	takePos := getCheckerTakePos(startPos, endPos, game)
 
-		for _, movePos := range possibleMoves {
-			gameCopy := copyGame(game)
-			gameCopy.Board.Board[startPos.Y][startPos.X] = nil
-			gameCopy.Board.Board[endPos.Y][endPos.X] = piece
-
-			result := checkerMovesInCheck(endPos, movePos, piece, *gameCopy)
+	gameCopy := copyGame(game)
+	gameCopy.Board.Board[startPos.Y][startPos.X] = nil
+	gameCopy.Board.Board[endPos.Y][endPos.X] = piece
+	if takePos != nil {
+		gameCopy.Board.Board[takePos.Y][takePos.X] = nil
+	}
 
-			if !result {
-				return false
-			}
+	if takePos == nil || !checkCheckerNextJumps(endPos, *piece, *gameCopy) {
+		return GetInCheck(*gameCopy)
+	}
 
+	for _, movePos := range getCheckerJumps(endPos, *piece, *gameCopy) {
+		if !checkerMovesInCheck(endPos, movePos, piece, *gameCopy) {
+			return false
 		}
-
 	}
 
 	return true
@@ -6,6 +6,11 @@ import (
 )
 
 func checkValidPromote(move types.Move, piece types.Piece, game types.Game) error {
+	err := checkPromotePieceType(*move.Promote, piece)
+	if err != nil {
+		return err
+	}
+
 	switch piece.Type {
 	case types.Pawn:
 		err := checkPawnCheckerPromote(move, piece, game)
@@ -27,6 +32,22 @@ func checkValidPromote(move types.Move, piece types.Piece, game types.Game) erro
 	return nil
 }
 
+func checkPromotePieceType(promote int, piece types.Piece) error {
+	if piece.Type == types.Pawn {
+		_, ok := types.ChessPromotePieceToChar[promote]
+		if !ok || promote == types.Pawn {
+			return fmt.Errorf("Pawn must promote to a knight, bishop, rook or queen")
+		}
+		return nil
+	}
+
+	if promote != 0 {
+		return fmt.Errorf("Only pawns can choose a promotion piece")
+	}
+
+	return nil
+}
+
 func checkPawnCheckerPromote(move types.Move, piece types.Piece, game types.Game) error {
 	var row int
 	if piece.Owner == 0 {
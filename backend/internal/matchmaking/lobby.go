@@ -0,0 +1,292 @@
+package matchmaking
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/KainoaGardner/csc/internal/config"
+	"github.com/KainoaGardner/csc/internal/engine"
+	"github.com/KainoaGardner/csc/internal/store"
+	"github.com/KainoaGardner/csc/internal/types"
+	"github.com/KainoaGardner/csc/internal/utils"
+	"github.com/gorilla/websocket"
+	"log"
+	"math/rand/v2"
+	"sync"
+	"time"
+)
+
+type Lobby struct {
+	Players map[string]*websocket.Conn //userID -> lobby conn
+	Tickets []Ticket                   //oldest first
+	Mutex   sync.Mutex
+}
+
+var lobby = Lobby{
+	Players: make(map[string]*websocket.Conn),
+}
+
+func AddPlayerToLobby(userID string, conn *websocket.Conn) {
+	lobby.Mutex.Lock()
+	oldConn, ok := lobby.Players[userID]
+	if ok && oldConn != conn {
+		oldConn.Close()
+	}
+	lobby.Players[userID] = conn
+	lobby.Mutex.Unlock()
+
+	log.Printf("Player %s connected to lobby", userID)
+}
+
+func removePlayerFromLobby(userID string, conn *websocket.Conn) {
+	lobby.Mutex.Lock()
+	defer lobby.Mutex.Unlock()
+
+	conn.Close()
+	if lobby.Players[userID] != conn {
+		return
+	}
+
+	delete(lobby.Players, userID)
+	removeTicketLocked(userID)
+}
+
+// lobby mutex must be held
+func removeTicketLocked(userID string) bool {
+	for i, ticket := range lobby.Tickets {
+		if ticket.UserID == userID {
+			lobby.Tickets = append(lobby.Tickets[:i], lobby.Tickets[i+1:]...)
+			return true
+		}
+	}
+
+	return false
+}
+
+func sendToPlayer(userID string, msg interface{}) {
+	lobby.Mutex.Lock()
+	defer lobby.Mutex.Unlock()
+
+	conn, ok := lobby.Players[userID]
+	if !ok {
+		return
+	}
+
+	err := conn.WriteJSON(msg)
+	if err != nil {
+		conn.Close()
+		delete(lobby.Players, userID)
+		removeTicketLocked(userID)
+	}
+}
+
+func sendError(userID string, err error) {
+	fmt.Println("Error", err)
+	data := types.Error{
+		Error: err.Error(),
+	}
+
+	response := types.OutgoingMessage{
+		Type: "error",
+		Data: data,
+	}
+
+	sendToPlayer(userID, response)
+}
+
+func HandleLobbyMessages(userID string, conn *websocket.Conn) {
+	defer func() {
+		log.Printf("Closing lobby connection for player %s", userID)
+		removePlayerFromLobby(userID, conn)
+	}()
+
+	for {
+		_, data, err := conn.ReadMessage()
+		if err != nil {
+			log.Printf("lobby read error (player=%s): %v", userID, err)
+			return
+		}
+
+		var msg types.IncomingMessage
+		if err := json.Unmarshal(data, &msg); err != nil {
+			log.Printf("bad json (player=%s): %v data=%q", userID, err, data)
+			continue
+		}
+
+		switch msg.Type {
+		case "queue":
+			queueCase(userID, msg)
+		case "leave":
+			leaveCase(userID)
+		default:
+		}
+	}
+}
+
+func queueCase(userID string, msg types.IncomingMessage) {
+	postQueue, err := utils.ParseMsgJSON[types.PostQueue](msg)
+	if err != nil {
+		sendError(userID, err)
+		return
+	}
+
+	err = checkQueueRanges(postQueue)
+	if err != nil {
+		sendError(userID, err)
+		return
+	}
+
+	//preferred settings must make a valid game on their own
+	_, err = engine.SetupNewGame(setupPreferredGame(postQueue), userID)
+	if err != nil {
+		sendError(userID, err)
+		return
+	}
+
+	ticket := Ticket{
+		UserID:   userID,
+		Queue:    postQueue,
+		JoinTime: time.Now().UTC(),
+	}
+
+	lobby.Mutex.Lock()
+	removeTicketLocked(userID)
+	lobby.Tickets = append(lobby.Tickets, ticket)
+	size := len(lobby.Tickets)
+	lobby.Mutex.Unlock()
+
+	sendQueueStatus(userID, true, size)
+}
+
+func leaveCase(userID string) {
+	lobby.Mutex.Lock()
+	removed := removeTicketLocked(userID)
+	size := len(lobby.Tickets)
+	lobby.Mutex.Unlock()
+
+	if !removed {
+		sendError(userID, fmt.Errorf("Not in queue"))
+		return
+	}
+
+	sendQueueStatus(userID, false, size)
+}
+
+func sendQueueStatus(userID string, queued bool, size int) {
+	data := types.QueueResponse{
+		Queued: queued,
+		Size:   size,
+	}
+
+	response := types.OutgoingMessage{
+		Type: "queue",
+		Data: data,
+	}
+	sendToPlayer(userID, response)
+}
+
+func StartPairing(interval time.Duration, store store.Store, config config.Config) {
+	ticker := time.NewTicker(interval)
+
+	go func() {
+		for range ticker.C {
+			pairPlayers(store, config)
+		}
+	}()
+}
+
+func pairPlayers(store store.Store, config config.Config) {
+	type match struct {
+		a      Ticket
+		b      Ticket
+		values [4]int64
+	}
+
+	currTime := time.Now().UTC()
+	var matches []match
+
+	lobby.Mutex.Lock()
+	paired := make(map[int]bool)
+	for i := range lobby.Tickets {
+		if paired[i] {
+			continue
+		}
+
+		for j := i + 1; j < len(lobby.Tickets); j++ {
+			if paired[j] {
+				continue
+			}
+
+			values, ok := getMatchValues(lobby.Tickets[i], lobby.Tickets[j], currTime)
+			if !ok {
+				continue
+			}
+
+			matches = append(matches, match{a: lobby.Tickets[i], b: lobby.Tickets[j], values: values})
+			paired[i] = true
+			paired[j] = true
+			break
+		}
+	}
+
+	var tickets []Ticket
+	for i, ticket := range lobby.Tickets {
+		if !paired[i] {
+			tickets = append(tickets, ticket)
+		}
+	}
+	lobby.Tickets = tickets
+	lobby.Mutex.Unlock()
+
+	for _, m := range matches {
+		createMatch(m.a, m.b, m.values, store, config)
+	}
+}
+
+func createMatch(a Ticket, b Ticket, values [4]int64, store store.Store, config config.Config) {
+	postGame := setupMatchGame(values, a.Queue.TimeControl)
+
+	game, err := engine.SetupNewGame(postGame, a.UserID)
+	if err != nil {
+		sendError(a.UserID, err)
+		sendError(b.UserID, err)
+		return
+	}
+
+	if rand.IntN(2) == 0 {
+		game.WhiteID = a.UserID
+		game.BlackID = b.UserID
+	} else {
+		game.WhiteID = b.UserID
+		game.BlackID = a.UserID
+	}
+	game.State = types.PlaceState
+
+	gameID, err := store.CreateGame(game)
+	if err != nil {
+		sendError(a.UserID, err)
+		sendError(b.UserID, err)
+		return
+	}
+
+	log.Printf("Matched %s and %s in game %s", game.WhiteID, game.BlackID, gameID)
+
+	data := types.PostGameResponse{
+		ID:          gameID,
+		WhiteID:     game.WhiteID,
+		BlackID:     game.BlackID,
+		Width:       game.Board.Width,
+		Height:      game.Board.Height,
+		Money:       game.Money,
+		StartTime:   game.Time,
+		TimeControl: game.TimeControl,
+		PlaceLine:   game.Board.PlaceLine,
+		State:       game.State,
+		Prices:      engine.GetPrices(*game),
+		Rules:       engine.GetRules(*game),
+	}
+
+	data.Color = "w"
+	sendToPlayer(game.WhiteID, types.OutgoingMessage{Type: "matched", Data: data})
+	data.Color = "b"
+	sendToPlayer(game.BlackID, types.OutgoingMessage{Type: "matched", Data: data})
+}
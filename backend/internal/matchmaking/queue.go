@@ -0,0 +1,120 @@
+package matchmaking
+
+import (
+	"fmt"
+	"github.com/KainoaGardner/csc/internal/types"
+	"time"
+)
+
+const (
+	widenInterval  = 10 * time.Second
+	maxWidenSteps  = 6
+	boardWidenStep = 1
+	moneyWidenStep = 100
+	timeWidenStep  = 60
+)
+
+// bounds a matched game can use, same limits as game setup
+var (
+	boardBounds = types.Range{Min: 2, Max: 20}
+	moneyBounds = types.Range{Min: 50, Max: 100000000}
+	timeBounds  = types.Range{Min: 0, Max: 100000}
+)
+
+type Ticket struct {
+	UserID   string
+	Queue    types.PostQueue
+	JoinTime time.Time
+}
+
+func checkQueueRanges(queue types.PostQueue) error {
+	ranges := []types.Range{queue.Width, queue.Height, queue.Money, queue.StartTime}
+	for _, r := range ranges {
+		if r.Min > r.Max {
+			return fmt.Errorf("Range min cannot be bigger than max")
+		}
+	}
+
+	return nil
+}
+
+func getWidenSteps(ticket Ticket, currTime time.Time) int64 {
+	steps := int64(currTime.Sub(ticket.JoinTime) / widenInterval)
+	return min(steps, maxWidenSteps)
+}
+
+func widenRange(r types.Range, step int64, steps int64, bounds types.Range) types.Range {
+	r.Min = max(r.Min-step*steps, bounds.Min)
+	r.Max = min(r.Max+step*steps, bounds.Max)
+	return r
+}
+
+func getSearchRanges(ticket Ticket, currTime time.Time) [4]types.Range {
+	steps := getWidenSteps(ticket, currTime)
+	queue := ticket.Queue
+
+	return [4]types.Range{
+		widenRange(queue.Width, boardWidenStep, steps, boardBounds),
+		widenRange(queue.Height, boardWidenStep, steps, boardBounds),
+		widenRange(queue.Money, moneyWidenStep, steps, moneyBounds),
+		widenRange(queue.StartTime, timeWidenStep, steps, timeBounds),
+	}
+}
+
+func getPreferredValues(queue types.PostQueue) [4]int64 {
+	return [4]int64{
+		getMidpoint(queue.Width),
+		getMidpoint(queue.Height),
+		getMidpoint(queue.Money),
+		getMidpoint(queue.StartTime),
+	}
+}
+
+func getMidpoint(r types.Range) int64 {
+	return r.Min + (r.Max-r.Min)/2
+}
+
+// values both players accept, as close to both preferences as possible
+func getMatchValues(a Ticket, b Ticket, currTime time.Time) ([4]int64, bool) {
+	var result [4]int64
+
+	if a.Queue.TimeControl != b.Queue.TimeControl {
+		return result, false
+	}
+
+	aRanges := getSearchRanges(a, currTime)
+	bRanges := getSearchRanges(b, currTime)
+	aPreferred := getPreferredValues(a.Queue)
+	bPreferred := getPreferredValues(b.Queue)
+
+	for i := range result {
+		low := max(aRanges[i].Min, bRanges[i].Min)
+		high := min(aRanges[i].Max, bRanges[i].Max)
+		if low > high {
+			return result, false
+		}
+
+		target := aPreferred[i] + (bPreferred[i]-aPreferred[i])/2
+		result[i] = min(max(target, low), high)
+	}
+
+	return result, true
+}
+
+func setupMatchGame(values [4]int64, timeControl types.PostTimeControl) types.PostGame {
+	var result types.PostGame
+
+	result.Width = int(values[0])
+	result.Height = int(values[1])
+	result.PlaceLine = result.Height / 2
+	result.Money = [2]int{int(values[2]), int(values[2])}
+	result.StartTime = [2]int64{values[3], values[3]}
+	result.TimeControl = timeControl
+	result.Public = false
+
+	return result
+}
+
+func setupPreferredGame(queue types.PostQueue) types.PostGame {
+	return setupMatchGame(getPreferredValues(queue), queue.TimeControl)
+}
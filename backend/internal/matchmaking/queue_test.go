@@ -0,0 +1,120 @@
+package matchmaking
+
+import (
+	"testing"
+	"time"
+
+	"github.com/KainoaGardner/csc/internal/types"
+)
+
+func setupTestTicket(width types.Range, waited time.Duration, currTime time.Time) Ticket {
+	return Ticket{
+		UserID: "user",
+		Queue: types.PostQueue{
+			Width:     width,
+			Height:    types.Range{Min: 8, Max: 8},
+			Money:     types.Range{Min: 100, Max: 100},
+			StartTime: types.Range{Min: 600, Max: 600},
+		},
+		JoinTime: currTime.Add(-waited),
+	}
+}
+
+func TestGetWidenSteps(t *testing.T) {
+	currTime := time.Now()
+
+	cases := []struct {
+		waited   time.Duration
+		expected int64
+	}{
+		{0, 0},
+		{9 * time.Second, 0},
+		{10 * time.Second, 1},
+		{35 * time.Second, 3},
+		{10 * time.Minute, maxWidenSteps},
+	}
+
+	for _, c := range cases {
+		ticket := setupTestTicket(types.Range{Min: 8, Max: 8}, c.waited, currTime)
+		steps := getWidenSteps(ticket, currTime)
+		if steps != c.expected {
+			t.Errorf("waited %v: got %d steps, expected %d", c.waited, steps, c.expected)
+		}
+	}
+}
+
+func TestWidenRange(t *testing.T) {
+	cases := []struct {
+		name     string
+		r        types.Range
+		steps    int64
+		expected types.Range
+	}{
+		{"no steps", types.Range{Min: 5, Max: 8}, 0, types.Range{Min: 5, Max: 8}},
+		{"widens both ends", types.Range{Min: 5, Max: 8}, 2, types.Range{Min: 3, Max: 10}},
+		{"clamped to bounds", types.Range{Min: 3, Max: 19}, 6, types.Range{Min: 2, Max: 20}},
+		{"already at bounds", types.Range{Min: 2, Max: 20}, 3, types.Range{Min: 2, Max: 20}},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			result := widenRange(c.r, boardWidenStep, c.steps, boardBounds)
+			if result != c.expected {
+				t.Errorf("got %+v, expected %+v", result, c.expected)
+			}
+		})
+	}
+}
+
+func TestGetMidpoint(t *testing.T) {
+	if midpoint := getMidpoint(types.Range{Min: 3, Max: 8}); midpoint != 5 {
+		t.Errorf("got midpoint %d, expected 5", midpoint)
+	}
+	if midpoint := getMidpoint(types.Range{Min: 600, Max: 600}); midpoint != 600 {
+		t.Errorf("got midpoint %d, expected 600", midpoint)
+	}
+}
+
+func TestGetMatchValues(t *testing.T) {
+	currTime := time.Now()
+
+	cases := []struct {
+		name    string
+		aWidth  types.Range
+		bWidth  types.Range
+		waited  time.Duration
+		ok      bool
+		width   int64
+		byoyomi bool
+	}{
+		{"single overlap", types.Range{Min: 6, Max: 8}, types.Range{Min: 8, Max: 10}, 0, true, 8, false},
+		{"between preferences", types.Range{Min: 6, Max: 10}, types.Range{Min: 8, Max: 12}, 0, true, 9, false},
+		{"target clamped to overlap", types.Range{Min: 4, Max: 12}, types.Range{Min: 10, Max: 10}, 0, true, 10, false},
+		{"no overlap", types.Range{Min: 4, Max: 6}, types.Range{Min: 9, Max: 10}, 0, false, 0, false},
+		{"overlap after widening", types.Range{Min: 4, Max: 6}, types.Range{Min: 9, Max: 10}, 20 * time.Second, true, 7, false},
+		{"different time controls", types.Range{Min: 8, Max: 8}, types.Range{Min: 8, Max: 8}, 0, false, 0, true},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			a := setupTestTicket(c.aWidth, c.waited, currTime)
+			b := setupTestTicket(c.bWidth, c.waited, currTime)
+			if c.byoyomi {
+				b.Queue.TimeControl = types.PostTimeControl{Type: types.Byoyomi, Periods: 3, PeriodTime: 10000}
+			}
+
+			values, ok := getMatchValues(a, b, currTime)
+			if ok != c.ok {
+				t.Fatalf("got match %v, expected %v", ok, c.ok)
+			}
+			if !ok {
+				return
+			}
+
+			expected := [4]int64{c.width, 8, 100, 600}
+			if values != expected {
+				t.Errorf("got values %v, expected %v", values, expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,147 @@
+package rating
+
+import (
+	"github.com/KainoaGardner/csc/internal/types"
+	"math"
+)
+
+const (
+	DefaultRating     = 1500.0
+	DefaultRD         = 350.0
+	DefaultVolatility = 0.06
+
+	glickoScale = 173.7178
+	tau         = 0.5 //how fast volatility can change
+	convergence = 0.000001
+)
+
+type Rating struct {
+	Rating     float64
+	RD         float64
+	Volatility float64
+}
+
+type Result struct {
+	Opponent Rating
+	Score    float64 //1 win, 0.5 tie, 0 loss
+}
+
+func NewRating() Rating {
+	return Rating{
+		Rating:     DefaultRating,
+		RD:         DefaultRD,
+		Volatility: DefaultVolatility,
+	}
+}
+
+// players from before ratings were tracked have zero values
+func SetupRating(rating float64, rd float64, volatility float64) Rating {
+	if rd <= 0 || volatility <= 0 {
+		return NewRating()
+	}
+
+	return Rating{
+		Rating:     rating,
+		RD:         rd,
+		Volatility: volatility,
+	}
+}
+
+func GetScores(winner int) (float64, float64) {
+	switch winner {
+	case types.White:
+		return 1, 0
+	case types.Black:
+		return 0, 1
+	}
+
+	return 0.5, 0.5
+}
+
+// one rating period made up of the given games
+func Update(player Rating, results []Result) Rating {
+	mu := (player.Rating - DefaultRating) / glickoScale
+	phi := player.RD / glickoScale
+
+	if len(results) == 0 {
+		phiStar := math.Sqrt(phi*phi + player.Volatility*player.Volatility)
+		player.RD = math.Min(phiStar*glickoScale, DefaultRD)
+		return player
+	}
+
+	var vInverse float64
+	var deltaSum float64
+	for _, result := range results {
+		muJ := (result.Opponent.Rating - DefaultRating) / glickoScale
+		phiJ := result.Opponent.RD / glickoScale
+
+		g := getG(phiJ)
+		e := getE(mu, muJ, g)
+
+		vInverse += g * g * e * (1 - e)
+		deltaSum += g * (result.Score - e)
+	}
+
+	v := 1 / vInverse
+	delta := v * deltaSum
+
+	volatility := getNewVolatility(phi, v, delta, player.Volatility)
+
+	phiStar := math.Sqrt(phi*phi + volatility*volatility)
+	newPhi := 1 / math.Sqrt(1/(phiStar*phiStar)+1/v)
+	newMu := mu + newPhi*newPhi*deltaSum
+
+	return Rating{
+		Rating:     newMu*glickoScale + DefaultRating,
+		RD:         math.Min(newPhi*glickoScale, DefaultRD),
+		Volatility: volatility,
+	}
+}
+
+func getG(phi float64) float64 {
+	return 1 / math.Sqrt(1+3*phi*phi/(math.Pi*math.Pi))
+}
+
+func getE(mu float64, muJ float64, g float64) float64 {
+	return 1 / (1 + math.Exp(-g*(mu-muJ)))
+}
+
+// illinois algorithm from the glicko-2 paper
+func getNewVolatility(phi float64, v float64, delta float64, volatility float64) float64 {
+	a := math.Log(volatility * volatility)
+	f := func(x float64) float64 {
+		ex := math.Exp(x)
+		num := ex * (delta*delta - phi*phi - v - ex)
+		den := 2 * (phi*phi + v + ex) * (phi*phi + v + ex)
+		return num/den - (x-a)/(tau*tau)
+	}
+
+	A := a
+	var B float64
+	if delta*delta > phi*phi+v {
+		B = math.Log(delta*delta - phi*phi - v)
+	} else {
+		k := 1.0
+		for f(a-k*tau) < 0 {
+			k++
+		}
+		B = a - k*tau
+	}
+
+	fA := f(A)
+	fB := f(B)
+	for math.Abs(B-A) > convergence {
+		C := A + (A-B)*fA/(fB-fA)
+		fC := f(C)
+		if fC*fB <= 0 {
+			A = B
+			fA = fB
+		} else {
+			fA /= 2
+		}
+		B = C
+		fB = fC
+	}
+
+	return math.Exp(A / 2)
+}
@@ -0,0 +1,54 @@
+package rating
+
+import (
+	"math"
+	"testing"
+
+	"github.com/KainoaGardner/csc/internal/types"
+)
+
+func checkClose(t *testing.T, name string, got float64, expected float64, tolerance float64) {
+	t.Helper()
+	if math.Abs(got-expected) > tolerance {
+		t.Errorf("got %s %f, expected %f", name, got, expected)
+	}
+}
+
+// worked example from Glickman's glicko-2 paper
+func TestUpdatePaperExample(t *testing.T) {
+	player := Rating{Rating: 1500, RD: 200, Volatility: 0.06}
+	results := []Result{
+		{Opponent: Rating{Rating: 1400, RD: 30, Volatility: 0.06}, Score: 1},
+		{Opponent: Rating{Rating: 1550, RD: 100, Volatility: 0.06}, Score: 0},
+		{Opponent: Rating{Rating: 1700, RD: 300, Volatility: 0.06}, Score: 0},
+	}
+
+	result := Update(player, results)
+	checkClose(t, "rating", result.Rating, 1464.06, 0.01)
+	checkClose(t, "rd", result.RD, 151.52, 0.01)
+	checkClose(t, "volatility", result.Volatility, 0.05999, 0.00001)
+}
+
+func TestUpdateSingleGame(t *testing.T) {
+	white := NewRating()
+	black := NewRating()
+
+	whiteScore, blackScore := GetScores(types.White)
+	newWhite := Update(white, []Result{{Opponent: black, Score: whiteScore}})
+	newBlack := Update(black, []Result{{Opponent: white, Score: blackScore}})
+
+	if newWhite.Rating <= white.Rating || newBlack.Rating >= black.Rating {
+		t.Errorf("winner should gain and loser should lose rating, got %f and %f", newWhite.Rating, newBlack.Rating)
+	}
+	checkClose(t, "rating sum", newWhite.Rating+newBlack.Rating, 2*DefaultRating, 0.01)
+	if newWhite.RD >= white.RD {
+		t.Errorf("rd should shrink after a game, got %f", newWhite.RD)
+	}
+
+	whiteScore, blackScore = GetScores(types.Tie)
+	tieWhite := Update(white, []Result{{Opponent: black, Score: whiteScore}})
+	checkClose(t, "tie rating", tieWhite.Rating, DefaultRating, 0.01)
+	if blackScore != 0.5 {
+		t.Errorf("tie should score 0.5, got %f", blackScore)
+	}
+}
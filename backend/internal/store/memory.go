@@ -0,0 +1,676 @@
+package store
+
+import (
+	"fmt"
+	"github.com/KainoaGardner/csc/internal/types"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"sort"
+	"sync"
+)
+
+// keeps everything in maps for local development and tests
+// documents are copied through bson in and out so callers never share memory with the store
+type MemoryStore struct {
+	games     map[string]types.Game
+	gameLogs  map[string]types.GameLog
+	users     map[string]types.User
+	userStats map[string]types.UserStats //userID -> stats
+	mutex     sync.RWMutex
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		games:     make(map[string]types.Game),
+		gameLogs:  make(map[string]types.GameLog),
+		users:     make(map[string]types.User),
+		userStats: make(map[string]types.UserStats),
+	}
+}
+
+func copyDocument[T any](doc T) (T, error) {
+	var result T
+
+	data, err := bson.Marshal(doc)
+	if err != nil {
+		return result, err
+	}
+
+	err = bson.Unmarshal(data, &result)
+	return result, err
+}
+
+// same id check as the mongo store so bad ids fail the same way
+func checkID(id string) error {
+	_, err := primitive.ObjectIDFromHex(id)
+	return err
+}
+
+// object ids grow over time so sorting by them keeps insertion order
+func sortedKeys[T any](docs map[string]T) []string {
+	keys := make([]string, 0, len(docs))
+	for key := range docs {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// games
+
+func (s *MemoryStore) CreateGame(game *types.Game) (string, error) {
+	game.ID = primitive.NewObjectID()
+	stored, err := copyDocument(*game)
+	if err != nil {
+		return "", err
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.games[game.ID.Hex()] = stored
+	return game.ID.Hex(), nil
+}
+
+func (s *MemoryStore) FindGame(gameID string) (*types.Game, error) {
+	err := checkID(gameID)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	game, ok := s.games[gameID]
+	if !ok {
+		return nil, ErrNotFound
+	}
+
+	result, err := copyDocument(game)
+	if err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+func (s *MemoryStore) listGames(keep func(types.Game) bool) ([]types.Game, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	var result []types.Game
+	for _, key := range sortedKeys(s.games) {
+		if !keep(s.games[key]) {
+			continue
+		}
+
+		game, err := copyDocument(s.games[key])
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, game)
+	}
+
+	return result, nil
+}
+
+func (s *MemoryStore) listGamePointers(keep func(types.Game) bool) ([]*types.Game, error) {
+	games, err := s.listGames(keep)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []*types.Game
+	for i := range games {
+		result = append(result, &games[i])
+	}
+
+	return result, nil
+}
+
+func (s *MemoryStore) ListAllGames() ([]*types.Game, error) {
+	return s.listGamePointers(func(game types.Game) bool {
+		return true
+	})
+}
+
+func (s *MemoryStore) ListGamesInState(state int) ([]*types.Game, error) {
+	return s.listGamePointers(func(game types.Game) bool {
+		return game.State == state
+	})
+}
+
+func (s *MemoryStore) ListAllJoinableGames() ([]types.Game, error) {
+	return s.listGames(func(game types.Game) bool {
+		return game.State == types.ConnectState && game.Public
+	})
+}
+
+func (s *MemoryStore) DeleteAllGames() (int, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	count := len(s.games)
+	s.games = make(map[string]types.Game)
+	return count, nil
+}
+
+func (s *MemoryStore) DeleteGame(gameID string) (int, error) {
+	err := checkID(gameID)
+	if err != nil {
+		return 0, err
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return s.deleteGameLocked(gameID), nil
+}
+
+// store mutex must be held
+func (s *MemoryStore) deleteGameLocked(gameID string) int {
+	_, ok := s.games[gameID]
+	if !ok {
+		return 0
+	}
+
+	delete(s.games, gameID)
+	return 1
+}
+
+// only writes if nobody else updated the game since it was loaded
+// set copies the fields the matching mongo update would $set
+func (s *MemoryStore) updateGameVersion(gameID string, game types.Game, set func(stored *types.Game, game types.Game)) error {
+	err := checkID(gameID)
+	if err != nil {
+		return err
+	}
+
+	game, err = copyDocument(game)
+	if err != nil {
+		return err
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	stored, ok := s.games[gameID]
+	if !ok || stored.Version != game.Version {
+		return ErrVersionConflict
+	}
+
+	set(&stored, game)
+	stored.Version = game.Version + 1
+	s.games[gameID] = stored
+	return nil
+}
+
+func (s *MemoryStore) GamePlaceUpdate(gameID string, game types.Game) error {
+	return s.updateGameVersion(gameID, game, func(stored *types.Game, game types.Game) {
+		stored.Board.Board = game.Board.Board
+		stored.Money = game.Money
+		stored.Placements = game.Placements
+	})
+}
+
+func (s *MemoryStore) GameMoveUpdate(gameID string, game types.Game) error {
+	return s.updateGameVersion(gameID, game, func(stored *types.Game, game types.Game) {
+		id := stored.ID
+		*stored = game
+		stored.ID = id
+	})
+}
+
+func (s *MemoryStore) GameStateUpdate(gameID string, game types.Game) error {
+	return s.updateGameVersion(gameID, game, func(stored *types.Game, game types.Game) {
+		stored.State = game.State
+	})
+}
+
+func (s *MemoryStore) GameReadyUpdate(gameID string, game types.Game) error {
+	return s.updateGameVersion(gameID, game, func(stored *types.Game, game types.Game) {
+		stored.State = game.State
+		stored.Ready = game.Ready
+		stored.LastMoveTime = game.LastMoveTime
+	})
+}
+
+func (s *MemoryStore) GameDrawUpdate(gameID string, game types.Game) error {
+	return s.updateGameVersion(gameID, game, func(stored *types.Game, game types.Game) {
+		stored.Draw = game.Draw
+	})
+}
+
+func (s *MemoryStore) GameTakebackUpdate(gameID string, game types.Game) error {
+	return s.updateGameVersion(gameID, game, func(stored *types.Game, game types.Game) {
+		stored.Takeback = game.Takeback
+	})
+}
+
+// everything is checked before anything is written so a failure changes nothing
+func (s *MemoryStore) FinishGame(gameID string, gameLog types.GameLog, statsUpdates map[string]types.UpdateUserStats) error {
+	err := checkID(gameID)
+	if err != nil {
+		return err
+	}
+
+	gameLog, err = copyDocument(gameLog)
+	if err != nil {
+		return err
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	gameLogID, ok := s.findGameLogIDLocked(gameID)
+	if !ok {
+		return ErrNotFound
+	}
+
+	for userID := range statsUpdates {
+		err = checkID(userID)
+		if err != nil {
+			return err
+		}
+	}
+
+	s.gameLogs[gameLogID] = mergeGameLog(s.gameLogs[gameLogID], gameLog)
+	for userID, statsUpdate := range statsUpdates {
+		s.updateUserStatsLocked(userID, statsUpdate)
+	}
+	s.deleteGameLocked(gameID)
+
+	return nil
+}
+
+// game logs
+
+func (s *MemoryStore) CreateGameLog(gameLog *types.GameLog) (string, error) {
+	gameLog.ID = primitive.NewObjectID()
+	stored, err := copyDocument(*gameLog)
+	if err != nil {
+		return "", err
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.gameLogs[gameLog.ID.Hex()] = stored
+	return gameLog.ID.Hex(), nil
+}
+
+func (s *MemoryStore) FindGameLog(gameLogID string) (*types.GameLog, error) {
+	err := checkID(gameLogID)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	gameLog, ok := s.gameLogs[gameLogID]
+	if !ok {
+		return nil, ErrNotFound
+	}
+
+	result, err := copyDocument(gameLog)
+	if err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// store mutex must be held
+func (s *MemoryStore) findGameLogIDLocked(gameID string) (string, bool) {
+	for _, key := range sortedKeys(s.gameLogs) {
+		if s.gameLogs[key].GameID.Hex() == gameID {
+			return key, true
+		}
+	}
+
+	return "", false
+}
+
+func (s *MemoryStore) FindGameLogFromGameID(gameID string) (*types.GameLog, error) {
+	err := checkID(gameID)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	gameLogID, ok := s.findGameLogIDLocked(gameID)
+	if !ok {
+		return nil, ErrNotFound
+	}
+
+	result, err := copyDocument(s.gameLogs[gameLogID])
+	if err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+func (s *MemoryStore) ListAllGameLogs() ([]types.GameLog, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	var result []types.GameLog
+	for _, key := range sortedKeys(s.gameLogs) {
+		gameLog, err := copyDocument(s.gameLogs[key])
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, gameLog)
+	}
+
+	return result, nil
+}
+
+func (s *MemoryStore) DeleteAllGameLogs() (int, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	count := len(s.gameLogs)
+	s.gameLogs = make(map[string]types.GameLog)
+	return count, nil
+}
+
+// a missing log is not an error, same as an update matching nothing in mongo
+func (s *MemoryStore) updateGameLog(gameID string, update func(gameLog *types.GameLog)) error {
+	err := checkID(gameID)
+	if err != nil {
+		return err
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	gameLogID, ok := s.findGameLogIDLocked(gameID)
+	if !ok {
+		return nil
+	}
+
+	gameLog := s.gameLogs[gameLogID]
+	update(&gameLog)
+	s.gameLogs[gameLogID] = gameLog
+	return nil
+}
+
+func (s *MemoryStore) GameLogUpdate(gameID string, moveString string, fenString string) error {
+	return s.updateGameLog(gameID, func(gameLog *types.GameLog) {
+		gameLog.Moves = append(gameLog.Moves, moveString)
+		gameLog.BoardStates = append(gameLog.BoardStates, fenString)
+	})
+}
+
+func (s *MemoryStore) GameLogTakebackUpdate(gameID string, count int) error {
+	err := checkID(gameID)
+	if err != nil {
+		return err
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	gameLogID, ok := s.findGameLogIDLocked(gameID)
+	if !ok {
+		return ErrNotFound
+	}
+
+	gameLog := s.gameLogs[gameLogID]
+	gameLog.Moves = gameLog.Moves[:max(len(gameLog.Moves)-count, 0)]
+	gameLog.BoardStates = gameLog.BoardStates[:max(len(gameLog.BoardStates)-count, 0)]
+	s.gameLogs[gameLogID] = gameLog
+	return nil
+}
+
+// mongo $sets every field of the new log but keeps the stored id
+func mergeGameLog(stored types.GameLog, gameLog types.GameLog) types.GameLog {
+	if gameLog.ID.IsZero() {
+		gameLog.ID = stored.ID
+	}
+	return gameLog
+}
+
+func (s *MemoryStore) GameLogFinalUpdate(gameID string, gameLog types.GameLog) error {
+	gameLog, err := copyDocument(gameLog)
+	if err != nil {
+		return err
+	}
+
+	return s.updateGameLog(gameID, func(stored *types.GameLog) {
+		*stored = mergeGameLog(*stored, gameLog)
+	})
+}
+
+// users
+
+func (s *MemoryStore) CreateUser(newUser *types.User) (string, error) {
+	newUser.ID = primitive.NewObjectID()
+	stored, err := copyDocument(*newUser)
+	if err != nil {
+		return "", err
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.users[newUser.ID.Hex()] = stored
+	return newUser.ID.Hex(), nil
+}
+
+func (s *MemoryStore) FindUser(userID string) (*types.User, error) {
+	err := checkID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.findUser(func(user types.User) bool {
+		return user.ID.Hex() == userID
+	})
+}
+
+func (s *MemoryStore) findUser(match func(types.User) bool) (*types.User, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	for _, key := range sortedKeys(s.users) {
+		if !match(s.users[key]) {
+			continue
+		}
+
+		result, err := copyDocument(s.users[key])
+		if err != nil {
+			return nil, err
+		}
+		return &result, nil
+	}
+
+	return nil, ErrNotFound
+}
+
+func (s *MemoryStore) FindUserFromUsername(userName string) (*types.User, error) {
+	return s.findUser(func(user types.User) bool {
+		return user.Username == userName
+	})
+}
+
+func (s *MemoryStore) FindUserFromEmail(email string) (*types.User, error) {
+	return s.findUser(func(user types.User) bool {
+		return user.Email == email
+	})
+}
+
+func (s *MemoryStore) FindUserLogin(login types.User) error {
+	_, err := s.findUser(func(user types.User) bool {
+		return user.Username == login.Username || user.Email == login.Email
+	})
+	return err
+}
+
+func (s *MemoryStore) ListAllUsers() ([]types.User, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	var result []types.User
+	for _, key := range sortedKeys(s.users) {
+		user, err := copyDocument(s.users[key])
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, user)
+	}
+
+	return result, nil
+}
+
+func (s *MemoryStore) DeleteAllUsers() (int, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	count := len(s.users)
+	s.users = make(map[string]types.User)
+	return count, nil
+}
+
+func (s *MemoryStore) DeleteUser(userID string) (int, error) {
+	err := checkID(userID)
+	if err != nil {
+		return 0, err
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	_, ok := s.users[userID]
+	if !ok {
+		return 0, nil
+	}
+
+	delete(s.users, userID)
+	return 1, nil
+}
+
+// like the mongo store this returns an empty user
+func (s *MemoryStore) UpdateUserPassword(userID string, hashPassword string) (*types.User, error) {
+	var result types.User
+
+	err := checkID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	user, ok := s.users[userID]
+	if ok {
+		user.PasswordHash = hashPassword
+		s.users[userID] = user
+	}
+
+	return &result, nil
+}
+
+// user stats
+
+func (s *MemoryStore) CreateUserStats(userStats *types.UserStats) (string, error) {
+	userStats.ID = primitive.NewObjectID()
+	stored, err := copyDocument(*userStats)
+	if err != nil {
+		return "", err
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	userID := userStats.UserID.Hex()
+	_, ok := s.userStats[userID]
+	if ok {
+		return "", fmt.Errorf("User stats already exist")
+	}
+
+	s.userStats[userID] = stored
+	return userStats.ID.Hex(), nil
+}
+
+func (s *MemoryStore) FindUserStatsFromUserID(userID string) (*types.UserStats, error) {
+	err := checkID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	userStats, ok := s.userStats[userID]
+	if !ok {
+		return nil, ErrNotFound
+	}
+
+	result, err := copyDocument(userStats)
+	if err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+func (s *MemoryStore) ListAllUserStats() ([]types.UserStats, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	var result []types.UserStats
+	for _, key := range sortedKeys(s.userStats) {
+		userStats, err := copyDocument(s.userStats[key])
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, userStats)
+	}
+
+	return result, nil
+}
+
+func (s *MemoryStore) DeleteAllUserStats() (int, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	count := len(s.userStats)
+	s.userStats = make(map[string]types.UserStats)
+	return count, nil
+}
+
+func (s *MemoryStore) UpdateUserStats(userID string, userStatsUpdate types.UpdateUserStats) error {
+	err := checkID(userID)
+	if err != nil {
+		return err
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.updateUserStatsLocked(userID, userStatsUpdate)
+	return nil
+}
+
+// store mutex must be held
+func (s *MemoryStore) updateUserStatsLocked(userID string, userStatsUpdate types.UpdateUserStats) {
+	userStats, ok := s.userStats[userID]
+	if !ok {
+		return
+	}
+
+	userStats.GameLogs = append(userStats.GameLogs, userStatsUpdate.GameLog)
+	userStats.RatingHistory = append(userStats.RatingHistory, userStatsUpdate.RatingRecord)
+	userStats.GamesWon = userStatsUpdate.GamesWon
+	userStats.GamesPlayed = userStatsUpdate.GamesPlayed
+	userStats.Rating = userStatsUpdate.Rating
+	userStats.RD = userStatsUpdate.RD
+	userStats.Volatility = userStatsUpdate.Volatility
+	s.userStats[userID] = userStats
+}
@@ -0,0 +1,177 @@
+package store
+
+import (
+	"github.com/KainoaGardner/csc/internal/rating"
+	"github.com/KainoaGardner/csc/internal/types"
+	"sort"
+)
+
+// same numbers the mongo aggregation pipelines in db/stats.go produce
+
+func addGameResult(counts *types.ResultCounts, gameLog types.GameLog, color int) {
+	switch *gameLog.Winner {
+	case types.Tie:
+		counts.Draws++
+	case color:
+		counts.Wins++
+	default:
+		counts.Losses++
+	}
+}
+
+// store mutex must be held, games against the computer are unrated so they are left out
+func (s *MemoryStore) finishedGameLogsLocked(keep func(types.GameLog) bool) []types.GameLog {
+	var result []types.GameLog
+	for _, key := range sortedKeys(s.gameLogs) {
+		gameLog := s.gameLogs[key]
+		botGame := gameLog.WhiteID == types.BotID || gameLog.BlackID == types.BotID
+		if gameLog.Winner != nil && !botGame && keep(gameLog) {
+			result = append(result, gameLog)
+		}
+	}
+
+	return result
+}
+
+func getUserColor(gameLog types.GameLog, userID string) int {
+	if gameLog.WhiteID == userID {
+		return types.White
+	}
+	return types.Black
+}
+
+func getLeaderboardValue(entry types.LeaderboardEntry, sort string) float64 {
+	switch sort {
+	case "wins":
+		return float64(entry.Wins)
+	case "winRate":
+		return entry.WinRate
+	case "gamesPlayed":
+		return float64(entry.GamesPlayed)
+	default:
+		return entry.Rating
+	}
+}
+
+func (s *MemoryStore) FindLeaderboard(sortBy string, page int, limit int) ([]types.LeaderboardEntry, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	counts := make(map[string]*types.ResultCounts)
+	gameLogs := s.finishedGameLogsLocked(func(gameLog types.GameLog) bool {
+		return true
+	})
+	for _, gameLog := range gameLogs {
+		players := [2]string{gameLog.WhiteID, gameLog.BlackID}
+		for color, userID := range players {
+			_, ok := counts[userID]
+			if !ok {
+				counts[userID] = &types.ResultCounts{}
+			}
+			addGameResult(counts[userID], gameLog, color)
+		}
+	}
+
+	entries := []types.LeaderboardEntry{}
+	for userID, count := range counts {
+		entry := types.LeaderboardEntry{
+			UserID:      userID,
+			Rating:      rating.DefaultRating,
+			GamesPlayed: count.Wins + count.Losses + count.Draws,
+			Wins:        count.Wins,
+			Losses:      count.Losses,
+			Draws:       count.Draws,
+		}
+		entry.WinRate = float64(entry.Wins) / float64(entry.GamesPlayed)
+
+		user, ok := s.users[userID]
+		if ok {
+			entry.Username = user.Username
+		}
+
+		userStats, ok := s.userStats[userID]
+		if ok && userStats.Rating > 0 {
+			entry.Rating = userStats.Rating
+		}
+
+		entries = append(entries, entry)
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		a := getLeaderboardValue(entries[i], sortBy)
+		b := getLeaderboardValue(entries[j], sortBy)
+		if a != b {
+			return a > b
+		}
+		return entries[i].UserID < entries[j].UserID
+	})
+
+	start := min((page-1)*limit, len(entries))
+	end := min(start+limit, len(entries))
+	return entries[start:end], nil
+}
+
+func (s *MemoryStore) FindUserBreakdown(userID string) (*types.UserBreakdownResponse, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	result := types.UserBreakdownResponse{
+		UserID:  userID,
+		Reasons: map[string]types.ResultCounts{},
+	}
+
+	gameLogs := s.finishedGameLogsLocked(func(gameLog types.GameLog) bool {
+		return gameLog.WhiteID == userID || gameLog.BlackID == userID
+	})
+
+	moveCount := 0
+	for _, gameLog := range gameLogs {
+		color := getUserColor(gameLog, userID)
+		if color == types.White {
+			addGameResult(&result.White, gameLog, color)
+		} else {
+			addGameResult(&result.Black, gameLog, color)
+		}
+
+		counts := result.Reasons[gameLog.Reason]
+		addGameResult(&counts, gameLog, color)
+		result.Reasons[gameLog.Reason] = counts
+
+		moveCount += gameLog.MoveCount
+	}
+
+	result.GamesPlayed = len(gameLogs)
+	if result.GamesPlayed != 0 {
+		result.AverageMoveCount = float64(moveCount) / float64(result.GamesPlayed)
+	}
+
+	return &result, nil
+}
+
+func (s *MemoryStore) FindHeadToHead(userID string, opponentID string) (*types.HeadToHeadResponse, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	result := types.HeadToHeadResponse{
+		UserID:     userID,
+		OpponentID: opponentID,
+	}
+
+	gameLogs := s.finishedGameLogsLocked(func(gameLog types.GameLog) bool {
+		return (gameLog.WhiteID == userID && gameLog.BlackID == opponentID) ||
+			(gameLog.WhiteID == opponentID && gameLog.BlackID == userID)
+	})
+
+	for _, gameLog := range gameLogs {
+		color := getUserColor(gameLog, userID)
+		addGameResult(&result.Results, gameLog, color)
+		if color == types.White {
+			addGameResult(&result.White, gameLog, color)
+		} else {
+			addGameResult(&result.Black, gameLog, color)
+		}
+	}
+	result.GamesPlayed = len(gameLogs)
+
+	return &result, nil
+}
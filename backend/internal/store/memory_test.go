@@ -0,0 +1,234 @@
+package store
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/KainoaGardner/csc/internal/types"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestMemoryGameVersion(t *testing.T) {
+	s := NewMemoryStore()
+
+	gameID, err := s.CreateGame(&types.Game{State: types.PlaceState})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := s.FindGame(gameID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stale := *loaded
+
+	loaded.State = types.MoveState
+	err = s.GameStateUpdate(gameID, *loaded)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	stored, err := s.FindGame(gameID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stored.State != types.MoveState || stored.Version != 1 {
+		t.Errorf("got state %d version %d, expected state %d version 1", stored.State, stored.Version, types.MoveState)
+	}
+
+	cases := []struct {
+		name   string
+		gameID string
+		game   types.Game
+		err    error
+	}{
+		{"stale version", gameID, stale, ErrVersionConflict},
+		{"missing game", primitive.NewObjectID().Hex(), *stored, ErrVersionConflict},
+		{"current version", gameID, *stored, nil},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			err := s.GameDrawUpdate(c.gameID, c.game)
+			if !errors.Is(err, c.err) {
+				t.Errorf("got %v, expected %v", err, c.err)
+			}
+		})
+	}
+
+	err = s.GameDrawUpdate("bad id", *stored)
+	if err == nil || errors.Is(err, ErrVersionConflict) {
+		t.Errorf("bad ids should fail the id check, got %v", err)
+	}
+}
+
+type testPlayers struct {
+	a string
+	b string
+	c string
+}
+
+// a: 2 wins 1 draw, b: 3 losses, c: 1 win 1 draw
+func setupTestStatsStore(t *testing.T) (*MemoryStore, testPlayers) {
+	t.Helper()
+
+	s := NewMemoryStore()
+	players := testPlayers{
+		a: primitive.NewObjectID().Hex(),
+		b: primitive.NewObjectID().Hex(),
+		c: primitive.NewObjectID().Hex(),
+	}
+
+	white := types.White
+	black := types.Black
+	tie := types.Tie
+	gameLogs := []types.GameLog{
+		{WhiteID: players.a, BlackID: players.b, Winner: &white, Reason: "Checkmate", MoveCount: 20},
+		{WhiteID: players.a, BlackID: players.c, Winner: &tie, Reason: "Repitition", MoveCount: 40},
+		{WhiteID: players.b, BlackID: players.a, Winner: &black, Reason: "Checkmate", MoveCount: 30},
+		{WhiteID: players.c, BlackID: players.b, Winner: &white, Reason: "Time", MoveCount: 10},
+		{WhiteID: players.a, BlackID: players.b, MoveCount: 5}, //still being played
+	}
+	for i := range gameLogs {
+		_, err := s.CreateGameLog(&gameLogs[i])
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	userID, err := primitive.ObjectIDFromHex(players.a)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.users[players.a] = types.User{ID: userID, Username: "alice"}
+
+	userID, err = primitive.ObjectIDFromHex(players.c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = s.CreateUserStats(&types.UserStats{UserID: userID, Rating: 1700})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return s, players
+}
+
+func TestMemoryLeaderboard(t *testing.T) {
+	s, players := setupTestStatsStore(t)
+
+	cases := []struct {
+		name     string
+		sort     string
+		page     int
+		limit    int
+		expected []string
+	}{
+		{"wins", "wins", 1, 10, []string{players.a, players.c, players.b}},
+		{"win rate", "winRate", 1, 10, []string{players.a, players.c, players.b}},
+		{"games played ties by id", "gamesPlayed", 1, 10, []string{players.a, players.b, players.c}},
+		{"rating", "rating", 1, 10, []string{players.c, players.a, players.b}},
+		{"first page", "wins", 1, 2, []string{players.a, players.c}},
+		{"second page", "wins", 2, 2, []string{players.b}},
+		{"past the end", "wins", 3, 2, []string{}},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			entries, err := s.FindLeaderboard(c.sort, c.page, c.limit)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			result := []string{}
+			for _, entry := range entries {
+				result = append(result, entry.UserID)
+			}
+			if len(result) != len(c.expected) {
+				t.Fatalf("got %v, expected %v", result, c.expected)
+			}
+			for i := range result {
+				if result[i] != c.expected[i] {
+					t.Errorf("got %v, expected %v", result, c.expected)
+					break
+				}
+			}
+		})
+	}
+
+	entries, err := s.FindLeaderboard("wins", 1, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := types.LeaderboardEntry{
+		UserID:      players.a,
+		Username:    "alice",
+		Rating:      entries[0].Rating,
+		GamesPlayed: 3,
+		Wins:        2,
+		Draws:       1,
+		WinRate:     2.0 / 3.0,
+	}
+	if entries[0] != expected {
+		t.Errorf("got %+v, expected %+v", entries[0], expected)
+	}
+}
+
+func TestMemoryUserBreakdown(t *testing.T) {
+	s, players := setupTestStatsStore(t)
+
+	result, err := s.FindUserBreakdown(players.a)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if result.GamesPlayed != 3 || result.AverageMoveCount != 30 {
+		t.Errorf("got %d games averaging %v moves, expected 3 averaging 30", result.GamesPlayed, result.AverageMoveCount)
+	}
+	if result.White != (types.ResultCounts{Wins: 1, Draws: 1}) || result.Black != (types.ResultCounts{Wins: 1}) {
+		t.Errorf("got white %+v black %+v", result.White, result.Black)
+	}
+	if result.Reasons["Checkmate"] != (types.ResultCounts{Wins: 2}) || result.Reasons["Repitition"] != (types.ResultCounts{Draws: 1}) || len(result.Reasons) != 2 {
+		t.Errorf("got reasons %+v", result.Reasons)
+	}
+
+	result, err = s.FindUserBreakdown(primitive.NewObjectID().Hex())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.GamesPlayed != 0 || result.AverageMoveCount != 0 {
+		t.Errorf("users without games should have an empty breakdown, got %+v", result)
+	}
+}
+
+func TestMemoryHeadToHead(t *testing.T) {
+	s, players := setupTestStatsStore(t)
+
+	cases := []struct {
+		name     string
+		userID   string
+		opponent string
+		games    int
+		results  types.ResultCounts
+		white    types.ResultCounts
+		black    types.ResultCounts
+	}{
+		{"winner", players.a, players.b, 2, types.ResultCounts{Wins: 2}, types.ResultCounts{Wins: 1}, types.ResultCounts{Wins: 1}},
+		{"loser", players.b, players.a, 2, types.ResultCounts{Losses: 2}, types.ResultCounts{Losses: 1}, types.ResultCounts{Losses: 1}},
+		{"draw", players.c, players.a, 1, types.ResultCounts{Draws: 1}, types.ResultCounts{}, types.ResultCounts{Draws: 1}},
+		{"never played", players.a, primitive.NewObjectID().Hex(), 0, types.ResultCounts{}, types.ResultCounts{}, types.ResultCounts{}},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			result, err := s.FindHeadToHead(c.userID, c.opponent)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if result.GamesPlayed != c.games || result.Results != c.results || result.White != c.white || result.Black != c.black {
+				t.Errorf("got %d games results %+v white %+v black %+v", result.GamesPlayed, result.Results, result.White, result.Black)
+			}
+		})
+	}
+}
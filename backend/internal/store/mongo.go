@@ -0,0 +1,184 @@
+package store
+
+import (
+	"github.com/KainoaGardner/csc/internal/config"
+	"github.com/KainoaGardner/csc/internal/db"
+	"github.com/KainoaGardner/csc/internal/types"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type MongoStore struct {
+	client *mongo.Client
+	db     config.DB
+}
+
+func NewMongoStore(client *mongo.Client, db config.DB) *MongoStore {
+	return &MongoStore{
+		client: client,
+		db:     db,
+	}
+}
+
+// games
+
+func (s *MongoStore) CreateGame(game *types.Game) (string, error) {
+	return db.CreateGame(s.client, s.db, game)
+}
+
+func (s *MongoStore) FindGame(gameID string) (*types.Game, error) {
+	return db.FindGame(s.client, s.db, gameID)
+}
+
+func (s *MongoStore) ListAllGames() ([]*types.Game, error) {
+	return db.ListAllGames(s.client, s.db)
+}
+
+func (s *MongoStore) ListGamesInState(state int) ([]*types.Game, error) {
+	return db.ListGamesInState(s.client, s.db, state)
+}
+
+func (s *MongoStore) ListAllJoinableGames() ([]types.Game, error) {
+	return db.ListAllJoinableGames(s.client, s.db)
+}
+
+func (s *MongoStore) DeleteAllGames() (int, error) {
+	return db.DeleteAllGames(s.client, s.db)
+}
+
+func (s *MongoStore) DeleteGame(gameID string) (int, error) {
+	return db.DeleteGame(s.client, s.db, gameID)
+}
+
+func (s *MongoStore) GamePlaceUpdate(gameID string, game types.Game) error {
+	return db.GamePlaceUpdate(s.client, s.db, gameID, game)
+}
+
+func (s *MongoStore) GameMoveUpdate(gameID string, game types.Game) error {
+	return db.GameMoveUpdate(s.client, s.db, gameID, game)
+}
+
+func (s *MongoStore) GameStateUpdate(gameID string, game types.Game) error {
+	return db.GameStateUpdate(s.client, s.db, gameID, game)
+}
+
+func (s *MongoStore) GameReadyUpdate(gameID string, game types.Game) error {
+	return db.GameReadyUpdate(s.client, s.db, gameID, game)
+}
+
+func (s *MongoStore) GameDrawUpdate(gameID string, game types.Game) error {
+	return db.GameDrawUpdate(s.client, s.db, gameID, game)
+}
+
+func (s *MongoStore) GameTakebackUpdate(gameID string, game types.Game) error {
+	return db.GameTakebackUpdate(s.client, s.db, gameID, game)
+}
+
+func (s *MongoStore) FinishGame(gameID string, gameLog types.GameLog, statsUpdates map[string]types.UpdateUserStats) error {
+	return db.FinishGame(s.client, s.db, gameID, gameLog, statsUpdates)
+}
+
+// game logs
+
+func (s *MongoStore) CreateGameLog(gameLog *types.GameLog) (string, error) {
+	return db.CreateGameLog(s.client, s.db, gameLog)
+}
+
+func (s *MongoStore) FindGameLog(gameLogID string) (*types.GameLog, error) {
+	return db.FindGameLog(s.client, s.db, gameLogID)
+}
+
+func (s *MongoStore) FindGameLogFromGameID(gameID string) (*types.GameLog, error) {
+	return db.FindGameLogFromGameID(s.client, s.db, gameID)
+}
+
+func (s *MongoStore) ListAllGameLogs() ([]types.GameLog, error) {
+	return db.ListAllGameLogs(s.client, s.db)
+}
+
+func (s *MongoStore) DeleteAllGameLogs() (int, error) {
+	return db.DeleteAllGameLogs(s.client, s.db)
+}
+
+func (s *MongoStore) GameLogUpdate(gameID string, moveString string, fenString string) error {
+	return db.GameLogUpdate(s.client, s.db, gameID, moveString, fenString)
+}
+
+func (s *MongoStore) GameLogTakebackUpdate(gameID string, count int) error {
+	return db.GameLogTakebackUpdate(s.client, s.db, gameID, count)
+}
+
+func (s *MongoStore) GameLogFinalUpdate(gameID string, gameLog types.GameLog) error {
+	return db.GameLogFinalUpdate(s.client, s.db, gameID, gameLog)
+}
+
+func (s *MongoStore) FindLeaderboard(sort string, page int, limit int) ([]types.LeaderboardEntry, error) {
+	return db.FindLeaderboard(s.client, s.db, sort, page, limit)
+}
+
+func (s *MongoStore) FindUserBreakdown(userID string) (*types.UserBreakdownResponse, error) {
+	return db.FindUserBreakdown(s.client, s.db, userID)
+}
+
+func (s *MongoStore) FindHeadToHead(userID string, opponentID string) (*types.HeadToHeadResponse, error) {
+	return db.FindHeadToHead(s.client, s.db, userID, opponentID)
+}
+
+// users
+
+func (s *MongoStore) CreateUser(newUser *types.User) (string, error) {
+	return db.CreateUser(s.client, s.db, newUser)
+}
+
+func (s *MongoStore) FindUser(userID string) (*types.User, error) {
+	return db.FindUser(s.client, s.db, userID)
+}
+
+func (s *MongoStore) FindUserFromUsername(userName string) (*types.User, error) {
+	return db.FindUserFromUsername(s.client, s.db, userName)
+}
+
+func (s *MongoStore) FindUserFromEmail(email string) (*types.User, error) {
+	return db.FindUserFromEmail(s.client, s.db, email)
+}
+
+func (s *MongoStore) FindUserLogin(user types.User) error {
+	return db.FindUserLogin(s.client, s.db, user)
+}
+
+func (s *MongoStore) ListAllUsers() ([]types.User, error) {
+	return db.ListAllUsers(s.client, s.db)
+}
+
+func (s *MongoStore) DeleteAllUsers() (int, error) {
+	return db.DeleteAllUsers(s.client, s.db)
+}
+
+func (s *MongoStore) DeleteUser(userID string) (int, error) {
+	return db.DeleteUser(s.client, s.db, userID)
+}
+
+func (s *MongoStore) UpdateUserPassword(userID string, hashPassword string) (*types.User, error) {
+	return db.UpdateUserPassword(s.client, s.db, userID, hashPassword)
+}
+
+// user stats
+
+func (s *MongoStore) CreateUserStats(userStats *types.UserStats) (string, error) {
+	return db.CreateUserStats(s.client, s.db, userStats)
+}
+
+func (s *MongoStore) FindUserStatsFromUserID(userID string) (*types.UserStats, error) {
+	return db.FindUserStatsFromUserID(s.client, s.db, userID)
+}
+
+func (s *MongoStore) ListAllUserStats() ([]types.UserStats, error) {
+	return db.ListAllUserStats(s.client, s.db)
+}
+
+func (s *MongoStore) DeleteAllUserStats() (int, error) {
+	return db.DeleteAllUserStats(s.client, s.db)
+}
+
+func (s *MongoStore) UpdateUserStats(userID string, userStatsUpdate types.UpdateUserStats) error {
+	return db.UpdateUserStats(s.client, s.db, userID, userStatsUpdate)
+}
@@ -0,0 +1,78 @@
+package store
+
+import (
+	"github.com/KainoaGardner/csc/internal/db"
+	"github.com/KainoaGardner/csc/internal/types"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// both implementations return these so callers only check one value
+var (
+	ErrNotFound        = mongo.ErrNoDocuments
+	ErrVersionConflict = db.ErrVersionConflict
+)
+
+type GameStore interface {
+	CreateGame(game *types.Game) (string, error)
+	FindGame(gameID string) (*types.Game, error)
+	ListAllGames() ([]*types.Game, error)
+	ListGamesInState(state int) ([]*types.Game, error)
+	ListAllJoinableGames() ([]types.Game, error)
+	DeleteAllGames() (int, error)
+	DeleteGame(gameID string) (int, error)
+
+	//updates fail with ErrVersionConflict if the game changed since it was loaded
+	GamePlaceUpdate(gameID string, game types.Game) error
+	GameMoveUpdate(gameID string, game types.Game) error
+	GameStateUpdate(gameID string, game types.Game) error
+	GameReadyUpdate(gameID string, game types.Game) error
+	GameDrawUpdate(gameID string, game types.Game) error
+	GameTakebackUpdate(gameID string, game types.Game) error
+}
+
+type GameLogStore interface {
+	CreateGameLog(gameLog *types.GameLog) (string, error)
+	FindGameLog(gameLogID string) (*types.GameLog, error)
+	FindGameLogFromGameID(gameID string) (*types.GameLog, error)
+	ListAllGameLogs() ([]types.GameLog, error)
+	DeleteAllGameLogs() (int, error)
+	GameLogUpdate(gameID string, moveString string, fenString string) error
+	GameLogTakebackUpdate(gameID string, count int) error
+	GameLogFinalUpdate(gameID string, gameLog types.GameLog) error
+
+	FindLeaderboard(sort string, page int, limit int) ([]types.LeaderboardEntry, error)
+	FindUserBreakdown(userID string) (*types.UserBreakdownResponse, error)
+	FindHeadToHead(userID string, opponentID string) (*types.HeadToHeadResponse, error)
+}
+
+type UserStore interface {
+	CreateUser(newUser *types.User) (string, error)
+	FindUser(userID string) (*types.User, error)
+	FindUserFromUsername(userName string) (*types.User, error)
+	FindUserFromEmail(email string) (*types.User, error)
+	FindUserLogin(user types.User) error
+	ListAllUsers() ([]types.User, error)
+	DeleteAllUsers() (int, error)
+	DeleteUser(userID string) (int, error)
+	UpdateUserPassword(userID string, hashPassword string) (*types.User, error)
+
+	CreateUserStats(userStats *types.UserStats) (string, error)
+	FindUserStatsFromUserID(userID string) (*types.UserStats, error)
+	ListAllUserStats() ([]types.UserStats, error)
+	DeleteAllUserStats() (int, error)
+	UpdateUserStats(userID string, userStatsUpdate types.UpdateUserStats) error
+}
+
+type Store interface {
+	GameStore
+	GameLogStore
+	UserStore
+
+	//final log, both players stats and the game delete are written together or not at all
+	FinishGame(gameID string, gameLog types.GameLog, statsUpdates map[string]types.UpdateUserStats) error
+}
+
+var (
+	_ Store = (*MongoStore)(nil)
+	_ Store = (*MemoryStore)(nil)
+)
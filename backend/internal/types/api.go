@@ -17,9 +17,25 @@ type PostMove struct {
 }
 
 type PostMoveResponse struct {
-	ID   primitive.ObjectID `bson:"_id,omitempty" json:"_id"`
-	FEN  string             `json:"fen"`
-	Move string             `json:"move"`
+	ID    primitive.ObjectID `bson:"_id,omitempty" json:"_id"`
+	FEN   string             `json:"fen"`
+	Move  string             `json:"move"`
+	Clock ClockResponse      `json:"clock"`
+}
+
+type ClockResponse struct {
+	Time       [2]int64 `json:"time"`       //ms left at serverTime
+	Periods    [2]int   `json:"periods"`    //byoyomi periods left
+	Period     int64    `json:"period"`     //ms left in the running byoyomi period
+	Running    *int     `json:"running"`    //side whose clock runs, nil when stopped
+	StartsAt   int64    `json:"startsAt"`   //unix ms the running clock starts after the move buffer
+	ServerTime int64    `json:"serverTime"` //unix ms
+}
+
+type LegalMovesResponse struct {
+	ID    primitive.ObjectID `bson:"_id,omitempty" json:"_id"`
+	Turn  int                `json:"turn"`
+	Moves []string           `json:"moves"`
 }
 
 type PostPlace struct {
@@ -35,6 +51,38 @@ const (
 	MovePlaceEnum
 )
 
+const ( //army presets
+	ChessArmy = iota
+	ShogiArmy
+	CheckersArmy
+	RandomArmy
+)
+
+type PostArmy struct {
+	Preset int  `json:"preset"`
+	Place  bool `json:"place"` //place the army instead of only proposing it
+}
+
+// an army for a board that does not exist yet
+type PostArmyProposal struct {
+	Preset    int `json:"preset"`
+	Width     int `json:"width"`
+	Height    int `json:"height"`
+	PlaceLine int `json:"placeLine"`
+	Money     int `json:"money"`
+	Turn      int `json:"turn"`
+}
+
+type ArmyResponse struct {
+	ID     primitive.ObjectID `bson:"_id,omitempty" json:"_id"`
+	Preset int                `json:"preset"`
+	Pieces []PostPlace        `json:"pieces"` //in the order they are placed
+	Cost   int                `json:"cost"`
+	FEN    string             `json:"fen"` //board with the army placed
+	Money  [2]int             `json:"money"`
+	Placed bool               `json:"placed"`
+}
+
 type DeletePlace struct {
 	Position string `json:"position"`
 }
@@ -49,25 +97,62 @@ type PlaceResponse struct {
 }
 
 type PostGame struct {
-	Width     int      `json:"width"`
-	Height    int      `json:"height"`
-	Money     [2]int   `json:"money"`
-	StartTime [2]int64 `json:"startTime"`
-	PlaceLine int      `json:"placeLine"`
-	Public    bool     `json:"public"`
+	Width       int             `json:"width"`
+	Height      int             `json:"height"`
+	Money       [2]int          `json:"money"`
+	StartTime   [2]int64        `json:"startTime"`
+	TimeControl PostTimeControl `json:"timeControl"`
+	PlaceLine   int             `json:"placeLine"`
+	Public      bool            `json:"public"`
+	BotLevel    int             `json:"botLevel"`   //play against the computer unless NoBot
+	Prices      map[int]int     `json:"prices"`     //piece type -> cost, replaces the default cost
+	Disallowed  []int           `json:"disallowed"` //piece types that cannot be bought
+	Rules       *PostRuleSet    `json:"rules"`      //every rule when left out
+}
+
+// rules left out keep their default
+type PostRuleSet struct {
+	Nifu                 *bool `json:"nifu"`
+	Uchifuzume           *bool `json:"uchifuzume"`
+	EnPassant            *bool `json:"enPassant"`
+	Castling             *bool `json:"castling"`
+	Repetition           *bool `json:"repetition"`
+	FiftyMove            *bool `json:"fiftyMove"`
+	InsufficientMaterial *bool `json:"insufficientMaterial"`
+	ForcedCapture        *int  `json:"forcedCapture"`
+	FlyingKings          *bool `json:"flyingKings"`
+	BackwardCaptures     *bool `json:"backwardCaptures"`
+	Crazyhouse           *bool `json:"crazyhouse"`
+	RepetitionRule       *int  `json:"repetitionRule"`
+}
+
+// times in seconds like StartTime
+type PostTimeControl struct {
+	Type       int   `json:"type"`
+	Increment  int64 `json:"increment"`
+	Periods    int   `json:"periods"`
+	PeriodTime int64 `json:"periodTime"`
 }
 
 type PostGameResponse struct {
-	ID        string   `json:"_id"`
-	WhiteID   string   `json:"whiteID"`
-	BlackID   string   `json:"blackID"`
-	Color     string   `json:"color"`
-	Width     int      `json:"width"`
-	Height    int      `json:"height"`
-	Money     [2]int   `json:"money"`
-	StartTime [2]int64 `json:"startTime"`
-	State     int      `json:"state"`
-	PlaceLine int      `json:"placeLine"`
+	ID          string      `json:"_id"`
+	WhiteID     string      `json:"whiteID"`
+	BlackID     string      `json:"blackID"`
+	Color       string      `json:"color"`
+	Width       int         `json:"width"`
+	Height      int         `json:"height"`
+	Money       [2]int      `json:"money"`
+	StartTime   [2]int64    `json:"startTime"`
+	TimeControl TimeControl `json:"timeControl"`
+	State       int         `json:"state"`
+	PlaceLine   int         `json:"placeLine"`
+	Prices      map[int]int `json:"prices"`
+	Rules       RuleSet     `json:"rules"`
+}
+
+type PricesResponse struct {
+	ID     primitive.ObjectID `bson:"_id,omitempty" json:"_id"`
+	Prices map[int]int        `json:"prices"`
 }
 
 type GetGameResponse struct {
@@ -81,11 +166,15 @@ type GetGameResponse struct {
 	Reason        string             `bson:"reason" json:"reason"`
 	State         int                `bson:"state" json:"state"` //0 place,1 move,2 over
 	Time          [2]int64           `bson:"time" json:"time"`
+	TimeControl   TimeControl        `bson:"timeControl" json:"timeControl"`
+	Periods       [2]int             `bson:"periods" json:"periods"`
 	LastMoveTime  time.Time          `bson:"lastMoveTime" json:"lastMoveTime"`
 	Money         [2]int             `bson:"money" json:"money"`
 	Ready         [2]bool            `bson:"ready" json:"ready"`
 	Draw          [2]bool            `bson:"draw" json:"draw"`
+	Takeback      [2]bool            `bson:"takeback" json:"takeback"`
 	Public        bool               `json:"public"`
+	Rules         RuleSet            `json:"rules"`
 }
 
 type PostState struct {
@@ -101,6 +190,7 @@ type ReadyResponse struct {
 	State int                `json:"state"`
 	Ready [2]bool            `bson:"ready" json:"ready"`
 	FEN   string             `json:"fen"`
+	Clock ClockResponse      `json:"clock"`
 }
 
 type GameOverResponse struct {
@@ -114,12 +204,25 @@ type GameOverResponse struct {
 	State         int                `bson:"state" json:"state"` //0 place,1 move,2 over
 	LastMoveTime  time.Time          `bson:"lastMoveTime" json:"lastMoveTime"`
 	FEN           string             `json:"fen"`
+	Clock         ClockResponse      `json:"clock"`
 }
 
 type PostDrawRequest struct {
 	Draw bool `json:"draw"`
 }
 
+type PostTakebackRequest struct {
+	Takeback bool `json:"takeback"`
+}
+
+type TakebackResponse struct {
+	ID       primitive.ObjectID `bson:"_id,omitempty" json:"_id"`
+	Takeback [2]bool            `json:"takeback"`
+	Count    int                `json:"count"`
+	FEN      string             `json:"fen"`
+	Clock    ClockResponse      `json:"clock"`
+}
+
 //user api
 
 type PostUser struct {
@@ -136,9 +239,13 @@ type UserResponse struct {
 }
 
 type UpdateUserStats struct {
-	GamesPlayed int    `bson:"gamesPlayed"`
-	GamesWon    int    `bson:"gamesWon"`
-	GameLog     string `bson:"gameLog"`
+	GamesPlayed  int          `bson:"gamesPlayed"`
+	GamesWon     int          `bson:"gamesWon"`
+	GameLog      string       `bson:"gameLog"`
+	Rating       float64      `bson:"rating"`
+	RD           float64      `bson:"rd"`
+	Volatility   float64      `bson:"volatility"`
+	RatingRecord RatingRecord `bson:"ratingRecord"`
 }
 
 type PostLogin struct {
@@ -164,9 +271,54 @@ type PostResetPassword struct {
 }
 
 type UserStatsResponse struct {
-	GamesPlayed int      `json:"gamesPlayed"`
-	GamesWon    int      `json:"gamesWon"`
-	GameLog     []string `json:"gameLog"`
+	GamesPlayed   int            `json:"gamesPlayed"`
+	GamesWon      int            `json:"gamesWon"`
+	GameLog       []string       `json:"gameLog"`
+	Rating        float64        `json:"rating"`
+	RD            float64        `json:"rd"`
+	RatingHistory []RatingRecord `json:"ratingHistory"`
+}
+
+type LeaderboardEntry struct {
+	UserID      string  `bson:"userID" json:"userID"`
+	Username    string  `bson:"username" json:"username"`
+	Rating      float64 `bson:"rating" json:"rating"`
+	GamesPlayed int     `bson:"gamesPlayed" json:"gamesPlayed"`
+	Wins        int     `bson:"wins" json:"wins"`
+	Losses      int     `bson:"losses" json:"losses"`
+	Draws       int     `bson:"draws" json:"draws"`
+	WinRate     float64 `bson:"winRate" json:"winRate"`
+}
+
+type LeaderboardResponse struct {
+	Sort    string             `json:"sort"`
+	Page    int                `json:"page"`
+	Limit   int                `json:"limit"`
+	Entries []LeaderboardEntry `json:"entries"`
+}
+
+type ResultCounts struct {
+	Wins   int `json:"wins"`
+	Losses int `json:"losses"`
+	Draws  int `json:"draws"`
+}
+
+type UserBreakdownResponse struct {
+	UserID           string                  `json:"userID"`
+	GamesPlayed      int                     `json:"gamesPlayed"`
+	AverageMoveCount float64                 `json:"averageMoveCount"`
+	White            ResultCounts            `json:"white"`
+	Black            ResultCounts            `json:"black"`
+	Reasons          map[string]ResultCounts `json:"reasons"`
+}
+
+type HeadToHeadResponse struct {
+	UserID      string       `json:"userID"`
+	OpponentID  string       `json:"opponentID"`
+	GamesPlayed int          `json:"gamesPlayed"`
+	Results     ResultCounts `json:"results"`
+	White       ResultCounts `json:"white"`
+	Black       ResultCounts `json:"black"`
 }
 
 type JoinableGameResponse struct {
@@ -179,6 +331,61 @@ type JoinableGameResponse struct {
 	Money     [2]int             `json:"money"`
 }
 
+type SpectateResponse struct {
+	ID         primitive.ObjectID `bson:"_id,omitempty" json:"_id"`
+	WhiteID    string             `json:"whiteID"`
+	BlackID    string             `json:"blackID"`
+	State      int                `json:"state"`
+	FEN        string             `json:"fen"`
+	Spectators int                `json:"spectators"`
+	Clock      ClockResponse      `json:"clock"`
+}
+
+type DisconnectResponse struct {
+	ID       primitive.ObjectID `bson:"_id,omitempty" json:"_id"`
+	PlayerID string             `json:"playerID"`
+	Seconds  int                `json:"seconds"`
+	Deadline *time.Time         `json:"deadline,omitempty"`
+}
+
+type ReconnectResponse struct {
+	ID       primitive.ObjectID `bson:"_id,omitempty" json:"_id"`
+	PlayerID string             `json:"playerID"`
+	State    int                `json:"state"`
+	FEN      string             `json:"fen"`
+	Clock    ClockResponse      `json:"clock"`
+}
+
+type ReplayPositionResponse struct {
+	ID       primitive.ObjectID `bson:"_id,omitempty" json:"_id"`
+	Ply      int                `json:"ply"`
+	Plies    int                `json:"plies"`
+	FEN      string             `json:"fen"`
+	LastMove string             `json:"lastMove"` //empty at the starting position
+	Turn     int                `json:"turn"`
+	InCheck  bool               `json:"inCheck"`
+	Time     [2]int64           `json:"time"` //ms left after the last move
+	Winner   *int               `json:"winner,omitempty"`
+	Reason   string             `json:"reason,omitempty"`
+}
+
+type ReplayStateResponse struct {
+	ID      primitive.ObjectID `bson:"_id,omitempty" json:"_id"`
+	WhiteID string             `json:"whiteID"`
+	BlackID string             `json:"blackID"`
+	Plies   int                `json:"plies"`
+	Playing bool               `json:"playing"`
+	Speed   float64            `json:"speed"`
+}
+
+type PostReplaySeek struct {
+	Ply int `json:"ply"`
+}
+
+type PostReplaySpeed struct {
+	Speed float64 `json:"speed"`
+}
+
 type IncomingMessage struct {
 	Type string          `json:"type"`
 	Data json.RawMessage `json:"data"`
@@ -1,6 +1,8 @@
 package types
 
 import (
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"time"
 )
@@ -19,33 +21,140 @@ type Board struct {
 }
 
 type Game struct {
-	ID              primitive.ObjectID `bson:"_id,omitempty" json:"_id"`
-	Board           Board              `bson:"board" json:"board"`
-	WhiteID         string             `bson:"whiteID" json:"whiteID"`
-	BlackID         string             `bson:"blackID" json:"blackID"`
-	Mochigoma       [MochigomaSize]int `bson:"mochigoma" json:"mochigoma"` //turn 0=0-6  turn 1=7-13 | order 歩香桂銀金角飛
-	Turn            int                `bson:"turn" json:"turn"`
-	MoveCount       int                `bson:"moveCount" json:"moveCount"`
-	HalfMoveCount   int                `bson:"halfMoveCount" json:"halfMoveCount"`
-	EnPassant       *Vec2              `bson:"enPassant" json:"enPassant"`
-	CheckerJump     *Vec2              `bson:"checkerJump" json:"checkerJump"`
-	Winner          *int               `bson:"winner" json:"winner"`
-	Reason          string             `bson:"reason" json:"reason"`
-	State           int                `bson:"state" json:"state"`
-	Time            [2]int64           `bson:"time" json:"time"`
-	LastMoveTime    time.Time          `bson:"lastMoveTime" json:"lastMoveTime"`
-	Money           [2]int             `bson:"money" json:"money"`
-	Ready           [2]bool            `bson:"ready" json:"ready"`
-	Draw            [2]bool            `bson:"draw" json:"draw"`
-	PositionHistory map[string]int     `bson:"positionHistory" json:"positionHistory"`
-	Public          bool               `bson:"public"`
+	ID              primitive.ObjectID        `bson:"_id,omitempty" json:"_id"`
+	Board           Board                     `bson:"board" json:"board"`
+	WhiteID         string                    `bson:"whiteID" json:"whiteID"`
+	BlackID         string                    `bson:"blackID" json:"blackID"`
+	Mochigoma       [MochigomaSize]int        `bson:"mochigoma" json:"mochigoma"` //turn 0=0-6  turn 1=7-13 | order 歩香桂銀金角飛
+	Hand            [HandSize]int             `bson:"hand" json:"hand"`           //crazyhouse captures turn 0=0-5 turn 1=6-11 | order pawn knight bishop rook queen checker
+	Turn            int                       `bson:"turn" json:"turn"`
+	MoveCount       int                       `bson:"moveCount" json:"moveCount"`
+	HalfMoveCount   int                       `bson:"halfMoveCount" json:"halfMoveCount"`
+	EnPassant       *Vec2                     `bson:"enPassant" json:"enPassant"`
+	CheckerJump     *Vec2                     `bson:"checkerJump" json:"checkerJump"`
+	Winner          *int                      `bson:"winner" json:"winner"`
+	Reason          string                    `bson:"reason" json:"reason"`
+	State           int                       `bson:"state" json:"state"`
+	Time            [2]int64                  `bson:"time" json:"time"`
+	TimeControl     TimeControl               `bson:"timeControl" json:"timeControl"`
+	Periods         [2]int                    `bson:"periods" json:"periods"` //byoyomi periods left
+	LastMoveTime    time.Time                 `bson:"lastMoveTime" json:"lastMoveTime"`
+	Money           [2]int                    `bson:"money" json:"money"`
+	StartMoney      [2]int                    `bson:"startMoney" json:"startMoney"`
+	Ready           [2]bool                   `bson:"ready" json:"ready"`
+	Draw            [2]bool                   `bson:"draw" json:"draw"`
+	Takeback        [2]bool                   `bson:"takeback" json:"takeback"`
+	PositionHistory map[string]PositionRecord `bson:"positionHistory" json:"positionHistory"`
+	StateHistory    []string                  `bson:"stateHistory" json:"stateHistory"`   //fen before each move
+	PeriodHistory   [][2]int                  `bson:"periodHistory" json:"periodHistory"` //byoyomi periods before each move
+	Placements      []PlacementRecord         `bson:"placements" json:"placements"`
+	Prices          map[int]int               `bson:"prices" json:"prices"` //piece type -> cost, missing types cannot be bought
+	Rules           *RuleSet                  `bson:"rules" json:"rules"`   //nil plays every rule
+	Public          bool                      `bson:"public"`
+	BotLevel        int                       `bson:"botLevel" json:"botLevel"` //NoBot unless black is the computer
+	Version         int64                     `bson:"version" json:"version"`   //bumped on every write
 }
 
+type TimeControl struct {
+	Type       int   `bson:"type" json:"type"`
+	Increment  int64 `bson:"increment" json:"increment"` //ms fischer increment or bronstein/simple delay
+	Periods    int   `bson:"periods" json:"periods"`
+	PeriodTime int64 `bson:"periodTime" json:"periodTime"` //ms per byoyomi period
+}
+
+// rules the game creator can switch on or off
+type RuleSet struct {
+	Nifu                 bool `bson:"nifu" json:"nifu"`
+	Uchifuzume           bool `bson:"uchifuzume" json:"uchifuzume"`
+	EnPassant            bool `bson:"enPassant" json:"enPassant"`
+	Castling             bool `bson:"castling" json:"castling"`
+	Repetition           bool `bson:"repetition" json:"repetition"`
+	FiftyMove            bool `bson:"fiftyMove" json:"fiftyMove"`
+	InsufficientMaterial bool `bson:"insufficientMaterial" json:"insufficientMaterial"`
+	ForcedCapture        int  `bson:"forcedCapture" json:"forcedCapture"`       //NoForcedCapture unless a checker that can jump has to
+	FlyingKings          bool `bson:"flyingKings" json:"flyingKings"`           //checker kings slide and capture from any distance
+	BackwardCaptures     bool `bson:"backwardCaptures" json:"backwardCaptures"` //checker men can jump backward
+	Crazyhouse           bool `bson:"crazyhouse" json:"crazyhouse"`             //captured chess and checkers pieces go to the hand too
+	RepetitionRule       int  `bson:"repetitionRule" json:"repetitionRule"`     //AutoRepetition plays sennichite once shogi pieces are in the position
+}
+
+// times a position came up and where it first did
+type PositionRecord struct {
+	Count int `bson:"count" json:"count"`
+	First int `bson:"first" json:"first"` //StateHistory index holding the first occurrence
+}
+
+// games saved before positions had records stored just the count
+func (r *PositionRecord) UnmarshalBSONValue(t bsontype.Type, data []byte) error {
+	raw := bson.RawValue{Type: t, Value: data}
+	count, ok := raw.AsInt64OK()
+	if ok {
+		*r = PositionRecord{Count: int(count)}
+		return nil
+	}
+
+	type plainRecord PositionRecord //no methods so decoding does not loop
+	var record plainRecord
+	err := raw.Unmarshal(&record)
+	if err != nil {
+		return err
+	}
+
+	*r = PositionRecord(record)
+	return nil
+}
+
+var DefaultRuleSet = RuleSet{
+	Nifu:                 true,
+	Uchifuzume:           true,
+	EnPassant:            true,
+	Castling:             true,
+	Repetition:           true,
+	FiftyMove:            true,
+	InsufficientMaterial: true,
+}
+
+const ( //forced capture rules
+	NoForcedCapture      = iota
+	CheckerForcedCapture //checkers have to jump but other pieces can still move instead
+	AllForcedCapture     //nothing but a jump is legal while a checker can jump
+)
+
+const ( //repetition rules
+	AutoRepetition       = iota
+	ChessRepetition      //draw on the third repetition
+	SennichiteRepetition //draw on the fourth repetition, lost by the side giving perpetual check
+)
+
+const BotID = "computer" //player id the computer plays under
+
+const ( //bot levels
+	NoBot = iota
+	BotEasy
+	BotMedium
+	BotHard
+	BotExpert
+)
+
+const ( //time controls
+	SuddenDeath = iota
+	FischerIncrement
+	BronsteinDelay
+	SimpleDelay
+	Byoyomi
+)
+
 const (
 	MochigomaSize        = 14
 	MochigomaBlackOffset = 7
 )
 
+const (
+	HandSize        = 12
+	HandBlackOffset = 6
+	HandDropOffset  = 7 //Move.Drop from here up is a hand piece instead of mochigoma
+)
+
 type Move struct {
 	Start   Vec2
 	End     Vec2
@@ -110,6 +219,34 @@ const (
 	CheckerKing
 )
 
+const (
+	HandPawn = iota
+	HandKnight
+	HandBishop
+	HandRook
+	HandQueen
+	HandChecker
+)
+
+var PieceToHandPiece = map[int]int{
+	Pawn:        HandPawn,
+	Knight:      HandKnight,
+	Bishop:      HandBishop,
+	Rook:        HandRook,
+	Queen:       HandQueen,
+	Checker:     HandChecker,
+	CheckerKing: HandChecker,
+}
+
+var HandPieceToDropPiece = map[int]int{
+	HandPawn:    Pawn,
+	HandKnight:  Knight,
+	HandBishop:  Bishop,
+	HandRook:    Rook,
+	HandQueen:   Queen,
+	HandChecker: Checker,
+}
+
 var ShogiDropCharToPiece = map[byte]int{
 	'P': Fu,
 	'L': Kyou,
@@ -225,6 +362,31 @@ var FenPieceToString = map[int]string{
 	CheckerKing: "KK",
 }
 
+var FenStringToPiece = map[string]int{
+	"CP": Pawn,
+	"CN": Knight,
+	"CB": Bishop,
+	"CR": Rook,
+	"CQ": Queen,
+	"CK": King,
+	"SP": Fu,
+	"SL": Kyou,
+	"SN": Kei,
+	"SG": Gin,
+	"SC": Kin,
+	"SB": Kaku,
+	"SR": Hi,
+	"SK": Ou,
+	"NP": To,
+	"NL": NariKyou,
+	"NN": NariKei,
+	"NG": NariGin,
+	"NB": Uma,
+	"NR": Ryuu,
+	"KC": Checker,
+	"KK": CheckerKing,
+}
+
 var PieceToCost = map[int]int{
 	King: 50,
 	Ou:   45,
@@ -20,6 +20,25 @@ type GameLog struct {
 	BoardWidth     int      `bson:"boardWidth" json:"boardWidth"`
 	BoardPlaceLine int      `bson:"boardPlaceLine" json:"boardPlaceLine"`
 
+	StartFEN    string      `bson:"startFEN" json:"startFEN"` //position once placement is done
+	Money       [2]int      `bson:"money" json:"money"`       //money each side started placement with
+	TimeControl TimeControl `bson:"timeControl" json:"timeControl"`
+	Rules       *RuleSet    `bson:"rules" json:"rules"` //nil plays every rule
+
+	Placements []PlacementRecord `bson:"placements" json:"placements"` //every placement phase action in order
+
 	Winner *int   `bson:"winner" json:"winner"`
 	Reason string `bson:"reason" json:"reason"`
 }
+
+type PlacementRecord struct {
+	Turn     int       `bson:"turn" json:"turn"`
+	Action   int       `bson:"action" json:"action"` //CreatePlaceEnum DeletePlaceEnum or MovePlaceEnum
+	Type     int       `bson:"type" json:"type"`
+	Position string    `bson:"position" json:"position"`
+	From     string    `bson:"from,omitempty" json:"from,omitempty"`
+	Cost     int       `bson:"cost" json:"cost"`   //negative when a piece is sold back
+	Money    int       `bson:"money" json:"money"` //left after the action
+	FEN      string    `bson:"fen" json:"fen"`
+	Date     time.Time `bson:"date" json:"date"`
+}
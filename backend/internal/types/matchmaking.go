@@ -0,0 +1,19 @@
+package types
+
+type Range struct {
+	Min int64 `json:"min"`
+	Max int64 `json:"max"`
+}
+
+type PostQueue struct {
+	Width       Range           `json:"width"`
+	Height      Range           `json:"height"`
+	Money       Range           `json:"money"`
+	StartTime   Range           `json:"startTime"` //seconds
+	TimeControl PostTimeControl `json:"timeControl"`
+}
+
+type QueueResponse struct {
+	Queued bool `json:"queued"`
+	Size   int  `json:"size"`
+}
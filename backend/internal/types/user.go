@@ -18,11 +18,23 @@ type User struct {
 }
 
 type UserStats struct {
-	ID          primitive.ObjectID `bson:"_id,omitempty"`
-	UserID      primitive.ObjectID `bson:"userID"`
-	GamesPlayed int                `bson:"gamesPlayed"`
-	GamesWon    int                `bson:"gamesWon"`
-	GameLogs    []string           `bson:"gameLogs"`
+	ID            primitive.ObjectID `bson:"_id,omitempty"`
+	UserID        primitive.ObjectID `bson:"userID"`
+	GamesPlayed   int                `bson:"gamesPlayed"`
+	GamesWon      int                `bson:"gamesWon"`
+	GameLogs      []string           `bson:"gameLogs"`
+	Rating        float64            `bson:"rating"`
+	RD            float64            `bson:"rd"`
+	Volatility    float64            `bson:"volatility"`
+	RatingHistory []RatingRecord     `bson:"ratingHistory"`
+}
+
+type RatingRecord struct {
+	GameLog string    `bson:"gameLog" json:"gameLog"`
+	Date    time.Time `bson:"date" json:"date"`
+	Rating  float64   `bson:"rating" json:"rating"`
+	RD      float64   `bson:"rd" json:"rd"`
+	Change  float64   `bson:"change" json:"change"`
 }
 
 type TokenClaims struct {
@@ -4,10 +4,9 @@ import (
 	"fmt"
 
 	"github.com/KainoaGardner/csc/internal/auth"
-	"github.com/KainoaGardner/csc/internal/config"
-	"github.com/KainoaGardner/csc/internal/db"
+	"github.com/KainoaGardner/csc/internal/rating"
+	"github.com/KainoaGardner/csc/internal/store"
 	"github.com/KainoaGardner/csc/internal/types"
-	"go.mongodb.org/mongo-driver/mongo"
 	"time"
 )
 
@@ -32,13 +31,19 @@ func SetupUserStats(newUser types.User) *types.UserStats {
 
 	result.UserID = newUser.ID
 	result.GameLogs = []string{}
+
+	newRating := rating.NewRating()
+	result.Rating = newRating.Rating
+	result.RD = newRating.RD
+	result.Volatility = newRating.Volatility
+	result.RatingHistory = []types.RatingRecord{}
 	return &result
 }
 
-func CheckUniqueLogin(client *mongo.Client, dbConfig config.DB, user types.User) error {
-	err := db.FindUserLogin(client, dbConfig, user)
+func CheckUniqueLogin(userStore store.UserStore, user types.User) error {
+	err := userStore.FindUserLogin(user)
 
-	if err == mongo.ErrNoDocuments {
+	if err == store.ErrNotFound {
 		return nil
 	} else if err != nil {
 		return err
@@ -55,3 +55,11 @@ func WriteResponse(w http.ResponseWriter, status int, message string, data any)
 	response.Data = data
 	return json.NewEncoder(w).Encode(response)
 }
+
+func WriteText(w http.ResponseWriter, status int, filename string, text string) error {
+	w.Header().Add("Content-Type", "text/plain; charset=utf-8")
+	w.Header().Add("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	w.WriteHeader(status)
+	_, err := w.Write([]byte(text))
+	return err
+}
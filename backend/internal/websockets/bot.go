@@ -0,0 +1,84 @@
+package websockets
+
+import (
+	"github.com/KainoaGardner/csc/internal/config"
+	"github.com/KainoaGardner/csc/internal/engine"
+	"github.com/KainoaGardner/csc/internal/store"
+	"github.com/KainoaGardner/csc/internal/types"
+	"log"
+	"sync"
+)
+
+var botGames = make(map[string]bool) //gameID -> bot is thinking
+var botGamesMutex sync.Mutex
+
+// starts the bot on its turn, does nothing if it is already thinking
+func ScheduleBotMove(gameID string, store store.Store, config config.Config) {
+	botGamesMutex.Lock()
+	if botGames[gameID] {
+		botGamesMutex.Unlock()
+		return
+	}
+	botGames[gameID] = true
+	botGamesMutex.Unlock()
+
+	go func() {
+		defer func() {
+			botGamesMutex.Lock()
+			delete(botGames, gameID)
+			botGamesMutex.Unlock()
+		}()
+
+		playBotMoves(gameID, store, config)
+	}()
+}
+
+// picks up games left waiting on the bot when the server stopped
+func StartBotMoves(store store.Store, config config.Config) error {
+	games, err := store.ListGamesInState(types.MoveState)
+	if err != nil {
+		return err
+	}
+
+	for _, game := range games {
+		if engine.IsBotTurn(*game) {
+			ScheduleBotMove(game.ID.Hex(), store, config)
+		}
+	}
+
+	return nil
+}
+
+// keeps moving while it is the bot's turn so checker jump chains finish
+func playBotMoves(gameID string, store store.Store, config config.Config) {
+	for {
+		game, err := store.FindGame(gameID)
+		if err != nil || !engine.IsBotTurn(*game) {
+			return
+		}
+
+		game, postMove, fen, err := engine.BotMoveCase(*game, store)
+		if err != nil {
+			log.Printf("bot move error (game=%s): %v", gameID, err)
+			return
+		}
+
+		if game.State == types.OverState {
+			GameOver(game, gameID, types.BotID, store, config)
+			return
+		}
+
+		data := types.PostMoveResponse{
+			ID:    game.ID,
+			FEN:   fen,
+			Move:  postMove.Move,
+			Clock: engine.GetClock(*game),
+		}
+
+		response := types.OutgoingMessage{
+			Type: "move",
+			Data: data,
+		}
+		BroadcastToGame(gameID, response)
+	}
+}
@@ -0,0 +1,40 @@
+package websockets
+
+import (
+	"github.com/KainoaGardner/csc/internal/engine"
+	"github.com/KainoaGardner/csc/internal/types"
+	"time"
+)
+
+func StartClockTicker(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+
+	go func() {
+		for range ticker.C {
+			broadcastClocks()
+		}
+	}()
+}
+
+// clocks come from the clock scheduler so ticks never load games
+func broadcastClocks() {
+	GameConnectionsMutex.Lock()
+	gameIDs := make([]string, 0, len(GameConnections))
+	for gameID := range GameConnections {
+		gameIDs = append(gameIDs, gameID)
+	}
+	GameConnectionsMutex.Unlock()
+
+	for _, gameID := range gameIDs {
+		clock, ok := engine.GetRunningClock(gameID)
+		if !ok {
+			continue
+		}
+
+		response := types.OutgoingMessage{
+			Type: "clock",
+			Data: clock,
+		}
+		BroadcastToGame(gameID, response)
+	}
+}
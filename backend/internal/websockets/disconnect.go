@@ -0,0 +1,153 @@
+package websockets
+
+import (
+	"github.com/KainoaGardner/csc/internal/config"
+	"github.com/KainoaGardner/csc/internal/engine"
+	"github.com/KainoaGardner/csc/internal/store"
+	"github.com/KainoaGardner/csc/internal/types"
+	"github.com/gorilla/websocket"
+	"log"
+	"time"
+)
+
+// returns false if the connection was already replaced by a newer one
+func removePlayerConn(gameID string, playerID string, conn *websocket.Conn) bool {
+	defer conn.Close()
+
+	room, ok := getGameRoom(gameID)
+	if !ok {
+		return true
+	}
+
+	room.Mutex.Lock()
+	defer room.Mutex.Unlock()
+
+	currConn, ok := room.Players[playerID]
+	if ok && currConn != conn {
+		return false
+	}
+
+	removePlayerLocked(room, playerID)
+	removeEmptyGameRoomLocked(gameID, room)
+	return true
+}
+
+// room mutex must be held
+func stopDisconnectTimerLocked(room *GameRoom, playerID string) bool {
+	timer, ok := room.Disconnected[playerID]
+	if !ok {
+		return false
+	}
+
+	timer.Stop()
+	delete(room.Disconnected, playerID)
+	return true
+}
+
+func startDisconnectGrace(gameID string, playerID string, store store.Store, config config.Config) {
+	game, err := store.FindGame(gameID)
+	if err != nil || game.State == types.OverState {
+		return
+	}
+
+	data := types.DisconnectResponse{
+		ID:       game.ID,
+		PlayerID: playerID,
+	}
+
+	room := getOrCreateGameRoom(gameID)
+
+	room.Mutex.Lock()
+	_, reconnected := room.Players[playerID]
+	if reconnected {
+		room.Mutex.Unlock()
+		return
+	}
+
+	stopDisconnectTimerLocked(room, playerID)
+	var timer *time.Timer
+	timer = time.AfterFunc(config.DisconnectGrace, func() {
+		disconnectExpired(gameID, playerID, timer, store, config)
+	})
+	room.Disconnected[playerID] = timer
+	room.Mutex.Unlock()
+
+	deadline := time.Now().UTC().Add(config.DisconnectGrace)
+	data.Seconds = int(config.DisconnectGrace.Seconds())
+	data.Deadline = &deadline
+
+	log.Printf("Player %s disconnected from game %s", playerID, gameID)
+
+	response := types.OutgoingMessage{
+		Type: "player disconnected",
+		Data: data,
+	}
+	BroadcastToGame(gameID, response)
+}
+
+func disconnectExpired(gameID string, playerID string, timer *time.Timer, store store.Store, config config.Config) {
+	room, ok := getGameRoom(gameID)
+	if !ok {
+		return
+	}
+
+	room.Mutex.Lock()
+	if room.Disconnected[playerID] != timer {
+		room.Mutex.Unlock()
+		return
+	}
+	delete(room.Disconnected, playerID)
+	removeEmptyGameRoomLocked(gameID, room)
+	room.Mutex.Unlock()
+
+	abandonCase(gameID, playerID, store, config)
+}
+
+func abandonCase(gameID string, playerID string, store store.Store, config config.Config) bool {
+	game, deleted, err := engine.AbandonCase(gameID, playerID, store)
+	if err != nil {
+		log.Printf("abandon error (player=%s game=%s): %v", playerID, gameID, err)
+		return false
+	}
+
+	//nothing to log or rate for a game that never started
+	if deleted {
+		broadcastGameOver(game, gameID, playerID)
+		return true
+	}
+
+	if game.State == types.OverState {
+		return GameOver(game, gameID, playerID, store, config)
+	}
+
+	return false
+}
+
+func ReconnectPlayer(gameID string, playerID string, store store.Store, config config.Config) {
+	game, err := store.FindGame(gameID)
+	if err != nil {
+		broadcastError(gameID, playerID, err)
+		return
+	}
+
+	fen, err := engine.ConvertBoardToString(*game)
+	if err != nil {
+		broadcastError(gameID, playerID, err)
+		return
+	}
+
+	data := types.ReconnectResponse{
+		ID:       game.ID,
+		PlayerID: playerID,
+		State:    game.State,
+		FEN:      fen,
+		Clock:    engine.GetClock(*game),
+	}
+
+	response := types.OutgoingMessage{
+		Type: "player reconnected",
+		Data: data,
+	}
+	BroadcastToGame(gameID, response)
+	ScheduleBotMove(gameID, store, config)
+}
@@ -0,0 +1,176 @@
+package websockets
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/KainoaGardner/csc/internal/engine"
+	"github.com/KainoaGardner/csc/internal/types"
+	"github.com/KainoaGardner/csc/internal/utils"
+	"github.com/gorilla/websocket"
+	"log"
+	"time"
+)
+
+const replayPlyDelay = time.Second //delay between plies at speed 1
+const minReplaySpeed = 0.25
+const maxReplaySpeed = 16.0
+
+// every viewer controls their own playback so replays do not share a room
+type replaySession struct {
+	gameLog types.GameLog
+	conn    *websocket.Conn
+	ply     int
+	playing bool
+	speed   float64
+}
+
+func (s *replaySession) send(msgType string, data interface{}) error {
+	response := types.OutgoingMessage{
+		Type: msgType,
+		Data: data,
+	}
+	return s.conn.WriteJSON(response)
+}
+
+func (s *replaySession) sendError(err error) error {
+	return s.send("error", types.Error{Error: err.Error()})
+}
+
+func (s *replaySession) sendState() error {
+	data := types.ReplayStateResponse{
+		ID:      s.gameLog.ID,
+		WhiteID: s.gameLog.WhiteID,
+		BlackID: s.gameLog.BlackID,
+		Plies:   engine.GetReplayPlies(s.gameLog),
+		Playing: s.playing,
+		Speed:   s.speed,
+	}
+	return s.send("replay", data)
+}
+
+func (s *replaySession) sendPosition() error {
+	position, err := engine.GetReplayPosition(s.gameLog, s.ply)
+	if err != nil {
+		return s.sendError(err)
+	}
+	return s.send("position", position)
+}
+
+func (s *replaySession) step() error {
+	s.ply++
+	if s.ply >= engine.GetReplayPlies(s.gameLog) {
+		s.playing = false
+		err := s.sendState()
+		if err != nil {
+			return err
+		}
+	}
+
+	return s.sendPosition()
+}
+
+func (s *replaySession) handleMessage(msg types.IncomingMessage) error {
+	switch msg.Type {
+	case "play":
+		s.playing = true
+		if s.ply >= engine.GetReplayPlies(s.gameLog) {
+			s.ply = 0
+			err := s.sendPosition()
+			if err != nil {
+				return err
+			}
+		}
+		return s.sendState()
+	case "pause":
+		s.playing = false
+		return s.sendState()
+	case "seek":
+		seek, err := utils.ParseMsgJSON[types.PostReplaySeek](msg)
+		if err != nil {
+			return s.sendError(err)
+		}
+
+		_, err = engine.GetReplayPosition(s.gameLog, seek.Ply)
+		if err != nil {
+			return s.sendError(err)
+		}
+		s.ply = seek.Ply
+		return s.sendPosition()
+	case "speed":
+		speed, err := utils.ParseMsgJSON[types.PostReplaySpeed](msg)
+		if err != nil {
+			return s.sendError(err)
+		}
+
+		if speed.Speed < minReplaySpeed || speed.Speed > maxReplaySpeed {
+			return s.sendError(fmt.Errorf("Speed must be between %g and %g", minReplaySpeed, maxReplaySpeed))
+		}
+		s.speed = speed.Speed
+		return s.sendState()
+	default:
+		return s.sendError(fmt.Errorf("Invalid replay command %s", msg.Type))
+	}
+}
+
+func readReplayMessages(conn *websocket.Conn, messages chan<- types.IncomingMessage, done <-chan struct{}) {
+	defer close(messages)
+
+	for {
+		_, data, err := conn.ReadMessage()
+		if err != nil {
+			return
+		}
+
+		var msg types.IncomingMessage
+		if err := json.Unmarshal(data, &msg); err != nil {
+			log.Printf("bad json (replay): %v data=%q", err, data)
+			continue
+		}
+
+		select {
+		case messages <- msg:
+		case <-done:
+			return
+		}
+	}
+}
+
+// streams a finished game, viewers send play pause seek and speed
+func HandleReplay(gameLog types.GameLog, conn *websocket.Conn) {
+	session := replaySession{
+		gameLog: gameLog,
+		conn:    conn,
+		speed:   1,
+	}
+
+	messages := make(chan types.IncomingMessage)
+	done := make(chan struct{})
+	defer func() {
+		log.Printf("Closing replay connection for game log %s", gameLog.ID.Hex())
+		close(done)
+		conn.Close()
+	}()
+	go readReplayMessages(conn, messages, done)
+
+	err := session.sendState()
+	if err == nil {
+		err = session.sendPosition()
+	}
+
+	for err == nil {
+		var tick <-chan time.Time
+		if session.playing {
+			tick = time.After(time.Duration(float64(replayPlyDelay) / session.speed))
+		}
+
+		select {
+		case <-tick:
+			err = session.step()
+		case msg, ok := <-messages:
+			if !ok {
+				return
+			}
+			err = session.handleMessage(msg)
+		}
+	}
+}
@@ -0,0 +1,110 @@
+package websockets
+
+import (
+	"github.com/KainoaGardner/csc/internal/types"
+	"github.com/gorilla/websocket"
+	"log"
+)
+
+func AddSpectatorToGame(gameID string, conn *websocket.Conn, data types.SpectateResponse) {
+	room := getOrCreateGameRoom(gameID)
+
+	room.Mutex.Lock()
+	room.Spectators[conn] = true
+	data.Spectators = len(room.Spectators)
+
+	response := types.OutgoingMessage{
+		Type: "spectate",
+		Data: data,
+	}
+	err := conn.WriteJSON(response)
+	if err != nil {
+		removeSpectatorLocked(room, conn)
+		removeEmptyGameRoomLocked(gameID, room)
+	}
+	room.Mutex.Unlock()
+
+	log.Printf("Spectator connected to game %s", gameID)
+	broadcastSpectatorCount(gameID)
+}
+
+func RemoveSpectatorFromGame(gameID string, conn *websocket.Conn) {
+	room, ok := getGameRoom(gameID)
+	if !ok {
+		return
+	}
+
+	room.Mutex.Lock()
+	removeSpectatorLocked(room, conn)
+	removeEmptyGameRoomLocked(gameID, room)
+	room.Mutex.Unlock()
+
+	broadcastSpectatorCount(gameID)
+}
+
+func GetSpectatorCount(gameID string) int {
+	room, ok := getGameRoom(gameID)
+	if !ok {
+		return 0
+	}
+
+	room.Mutex.Lock()
+	defer room.Mutex.Unlock()
+
+	return len(room.Spectators)
+}
+
+func broadcastSpectatorCount(gameID string) {
+	data := map[string]interface{}{
+		"spectators": GetSpectatorCount(gameID),
+	}
+
+	response := types.OutgoingMessage{
+		Type: "spectators",
+		Data: data,
+	}
+	BroadcastToGame(gameID, response)
+}
+
+func sendToSpectator(gameID string, conn *websocket.Conn, msg interface{}) {
+	room, ok := getGameRoom(gameID)
+	if !ok {
+		return
+	}
+
+	room.Mutex.Lock()
+	defer room.Mutex.Unlock()
+
+	_, ok = room.Spectators[conn]
+	if !ok {
+		return
+	}
+
+	err := conn.WriteJSON(msg)
+	if err != nil {
+		removeSpectatorLocked(room, conn)
+		removeEmptyGameRoomLocked(gameID, room)
+	}
+}
+
+func HandleSpectatorMessages(gameID string, conn *websocket.Conn) {
+	defer func() {
+		log.Printf("Closing spectator connection for game %s", gameID)
+		RemoveSpectatorFromGame(gameID, conn)
+	}()
+
+	for {
+		_, _, err := conn.ReadMessage()
+		if err != nil {
+			return
+		}
+
+		response := types.OutgoingMessage{
+			Type: "error",
+			Data: types.Error{
+				Error: "Spectators cannot send commands",
+			},
+		}
+		sendToSpectator(gameID, conn, response)
+	}
+}
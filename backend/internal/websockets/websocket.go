@@ -4,46 +4,72 @@ import (
 	"encoding/json"
 	"fmt"
 	"github.com/KainoaGardner/csc/internal/config"
-	"github.com/KainoaGardner/csc/internal/db"
 	"github.com/KainoaGardner/csc/internal/engine"
+	"github.com/KainoaGardner/csc/internal/store"
 	"github.com/KainoaGardner/csc/internal/types"
 	"github.com/KainoaGardner/csc/internal/utils"
 	"github.com/gorilla/websocket"
-	"go.mongodb.org/mongo-driver/mongo"
 	"log"
 	"sync"
+	"time"
 )
 
 type GameRoom struct {
-	Players map[string]*websocket.Conn //userID -> gameRoom
-	Mutex   sync.Mutex
+	Players      map[string]*websocket.Conn //userID -> gameRoom
+	Spectators   map[*websocket.Conn]bool
+	Disconnected map[string]*time.Timer //userID -> abandon timer
+	Mutex        sync.Mutex
 }
 
 var GameConnections = make(map[string]*GameRoom) //gameID -> gameRoom
 var GameConnectionsMutex sync.Mutex
 
-func AddPlayerToGame(gameID string, playerID string, conn *websocket.Conn) {
+func getOrCreateGameRoom(gameID string) *GameRoom {
 	GameConnectionsMutex.Lock()
+	defer GameConnectionsMutex.Unlock()
+
 	room, ok := GameConnections[gameID]
 	if !ok {
 		room = &GameRoom{
-			Players: make(map[string]*websocket.Conn),
+			Players:      make(map[string]*websocket.Conn),
+			Spectators:   make(map[*websocket.Conn]bool),
+			Disconnected: make(map[string]*time.Timer),
 		}
 		GameConnections[gameID] = room
 	}
-	GameConnectionsMutex.Unlock()
+
+	return room
+}
+
+func getGameRoom(gameID string) (*GameRoom, bool) {
+	GameConnectionsMutex.Lock()
+	defer GameConnectionsMutex.Unlock()
+
+	room, ok := GameConnections[gameID]
+	return room, ok
+}
+
+// returns true if the player is resuming a dropped or replaced connection
+func AddPlayerToGame(gameID string, playerID string, conn *websocket.Conn) bool {
+	room := getOrCreateGameRoom(gameID)
 
 	room.Mutex.Lock()
+	reconnected := stopDisconnectTimerLocked(room, playerID)
+
+	oldConn, ok := room.Players[playerID]
+	if ok && oldConn != conn {
+		oldConn.Close()
+		reconnected = true
+	}
 	room.Players[playerID] = conn
 	room.Mutex.Unlock()
 
 	log.Printf("Player %s connected to game %s", playerID, gameID)
+	return reconnected
 }
 
 func RemovePlayerFromGame(gameID string, playerID string) {
-	GameConnectionsMutex.Lock()
-	room, ok := GameConnections[gameID]
-	GameConnectionsMutex.Unlock()
+	room, ok := getGameRoom(gameID)
 	if !ok {
 		return
 	}
@@ -51,23 +77,43 @@ func RemovePlayerFromGame(gameID string, playerID string) {
 	room.Mutex.Lock()
 	defer room.Mutex.Unlock()
 
+	removePlayerLocked(room, playerID)
+	removeEmptyGameRoomLocked(gameID, room)
+}
+
+// room mutex must be held
+func removePlayerLocked(room *GameRoom, playerID string) {
 	conn, ok := room.Players[playerID]
 	if ok {
 		conn.Close()
 		delete(room.Players, playerID)
 	}
+}
 
-	if len(room.Players) == 0 {
-		GameConnectionsMutex.Lock()
-		delete(GameConnections, gameID)
-		GameConnectionsMutex.Unlock()
+// room mutex must be held
+func removeSpectatorLocked(room *GameRoom, conn *websocket.Conn) {
+	_, ok := room.Spectators[conn]
+	if ok {
+		conn.Close()
+		delete(room.Spectators, conn)
 	}
 }
 
-func BroadcastToGame(gameID string, msg interface{}) {
+// room mutex must be held
+func removeEmptyGameRoomLocked(gameID string, room *GameRoom) {
+	if len(room.Players) != 0 || len(room.Spectators) != 0 || len(room.Disconnected) != 0 {
+		return
+	}
+
 	GameConnectionsMutex.Lock()
-	room, ok := GameConnections[gameID]
+	if GameConnections[gameID] == room {
+		delete(GameConnections, gameID)
+	}
 	GameConnectionsMutex.Unlock()
+}
+
+func BroadcastToGame(gameID string, msg interface{}) {
+	room, ok := getGameRoom(gameID)
 	if !ok {
 		return
 	}
@@ -78,15 +124,22 @@ func BroadcastToGame(gameID string, msg interface{}) {
 	for playerID, conn := range room.Players {
 		err := conn.WriteJSON(msg)
 		if err != nil {
-			RemovePlayerFromGame(gameID, playerID)
+			removePlayerLocked(room, playerID)
 		}
 	}
+
+	for conn := range room.Spectators {
+		err := conn.WriteJSON(msg)
+		if err != nil {
+			removeSpectatorLocked(room, conn)
+		}
+	}
+
+	removeEmptyGameRoomLocked(gameID, room)
 }
 
 func BroadcastToPlayer(gameID string, playerID string, msg interface{}) {
-	GameConnectionsMutex.Lock()
-	room, ok := GameConnections[gameID]
-	GameConnectionsMutex.Unlock()
+	room, ok := getGameRoom(gameID)
 	if !ok {
 		return
 	}
@@ -101,39 +154,45 @@ func BroadcastToPlayer(gameID string, playerID string, msg interface{}) {
 
 	err := conn.WriteJSON(msg)
 	if err != nil {
-		RemovePlayerFromGame(gameID, playerID)
+		removePlayerLocked(room, playerID)
+		removeEmptyGameRoomLocked(gameID, room)
 	}
 }
 
 func DeleteGameRoom(gameID string) {
-	GameConnectionsMutex.Lock()
-	room, ok := GameConnections[gameID]
-	GameConnectionsMutex.Unlock()
+	room, ok := getGameRoom(gameID)
 	if !ok {
 		return
 	}
 
 	room.Mutex.Lock()
 	defer room.Mutex.Unlock()
-	for playerID, _ := range room.Players {
-		RemovePlayerFromGame(gameID, playerID)
+	for playerID := range room.Players {
+		removePlayerLocked(room, playerID)
 	}
+	for conn := range room.Spectators {
+		removeSpectatorLocked(room, conn)
+	}
+	for playerID := range room.Disconnected {
+		stopDisconnectTimerLocked(room, playerID)
+	}
+	removeEmptyGameRoomLocked(gameID, room)
 }
 
-func HandleMessages(gameID string, playerID string, conn *websocket.Conn, client *mongo.Client, config config.Config) {
+func HandleMessages(gameID string, playerID string, conn *websocket.Conn, store store.Store, config config.Config) {
+	var over bool
 	defer func() {
 		log.Printf("Closing connection for player %s", playerID)
-		RemovePlayerFromGame(gameID, playerID)
-		resignCase(gameID, playerID, client, config)
-		// DeleteGameRoom(gameID)
-		conn.Close()
+		dropped := removePlayerConn(gameID, playerID, conn)
+		if dropped && !over {
+			startDisconnectGrace(gameID, playerID, store, config)
+		}
 	}()
 
 	for {
 		_, data, err := conn.ReadMessage()
 		if err != nil {
 			log.Printf("read error (player=%s game=%s): %v", playerID, gameID, err)
-			resignCase(gameID, playerID, client, config)
 			return
 		}
 
@@ -143,20 +202,25 @@ func HandleMessages(gameID string, playerID string, conn *websocket.Conn, client
 			continue
 		}
 
-		var over bool
 		switch msg.Type {
 		case "join":
-			joinCase(gameID, playerID, client, config)
+			joinCase(gameID, playerID, store, config)
 		case "move":
-			over = moveCase(gameID, playerID, msg, client, config)
+			over = moveCase(gameID, playerID, msg, store, config)
+		case "legalMoves":
+			legalMovesCase(gameID, playerID, store, config)
 		case "place":
-			placeCase(gameID, playerID, msg, client, config)
+			placeCase(gameID, playerID, msg, store, config)
+		case "army":
+			armyCase(gameID, playerID, msg, store, config)
 		case "ready":
-			over = readyCase(gameID, playerID, msg, client, config)
+			over = readyCase(gameID, playerID, msg, store, config)
 		case "draw":
-			over = drawCase(gameID, playerID, msg, client, config)
+			over = drawCase(gameID, playerID, msg, store, config)
+		case "takeback":
+			takebackCase(gameID, playerID, msg, store, config)
 		case "resign":
-			over = resignCase(gameID, playerID, client, config)
+			over = resignCase(gameID, playerID, store, config)
 		default:
 		}
 
@@ -180,8 +244,8 @@ func broadcastError(gameID string, playerID string, err error) {
 	BroadcastToPlayer(gameID, playerID, response)
 }
 
-func joinCase(gameID string, playerID string, client *mongo.Client, config config.Config) {
-	game, err := engine.JoinGameCase(gameID, playerID, client, config)
+func joinCase(gameID string, playerID string, store store.Store, config config.Config) {
+	game, err := engine.JoinGameCase(gameID, playerID, store)
 	if err != nil {
 		broadcastError(gameID, playerID, err)
 		return
@@ -194,15 +258,18 @@ func joinCase(gameID string, playerID string, client *mongo.Client, config confi
 
 	if game.State == types.PlaceState {
 		data := types.PostGameResponse{
-			ID:        gameID,
-			WhiteID:   game.WhiteID,
-			BlackID:   game.BlackID,
-			Width:     game.Board.Width,
-			Height:    game.Board.Height,
-			Money:     game.Money,
-			StartTime: game.Time,
-			PlaceLine: game.Board.PlaceLine,
-			State:     game.State,
+			ID:          gameID,
+			WhiteID:     game.WhiteID,
+			BlackID:     game.BlackID,
+			Width:       game.Board.Width,
+			Height:      game.Board.Height,
+			Money:       game.Money,
+			StartTime:   game.Time,
+			TimeControl: game.TimeControl,
+			PlaceLine:   game.Board.PlaceLine,
+			State:       game.State,
+			Prices:      engine.GetPrices(*game),
+			Rules:       engine.GetRules(*game),
 		}
 
 		response := types.OutgoingMessage{
@@ -211,29 +278,32 @@ func joinCase(gameID string, playerID string, client *mongo.Client, config confi
 		}
 		BroadcastToGame(gameID, response)
 	}
+
+	ScheduleBotMove(gameID, store, config)
 }
 
-func moveCase(gameID string, playerID string, msg types.IncomingMessage, client *mongo.Client, config config.Config) bool {
+func moveCase(gameID string, playerID string, msg types.IncomingMessage, store store.Store, config config.Config) bool {
 	postMove, err := utils.ParseMsgJSON[types.PostMove](msg)
 	if err != nil {
 		broadcastError(gameID, playerID, err)
 		return false
 	}
 
-	game, fen, err := engine.MoveCase(gameID, playerID, postMove, client, config)
+	game, fen, err := engine.MoveCase(gameID, playerID, postMove, store)
 	if err != nil {
 		broadcastError(gameID, playerID, err)
 		return false
 	}
 
 	if game.State == types.OverState {
-		return GameOver(game, gameID, playerID, client, config)
+		return GameOver(game, gameID, playerID, store, config)
 
 	} else {
 		data := types.PostMoveResponse{
-			ID:   game.ID,
-			FEN:  fen,
-			Move: postMove.Move,
+			ID:    game.ID,
+			FEN:   fen,
+			Move:  postMove.Move,
+			Clock: engine.GetClock(*game),
 		}
 
 		response := types.OutgoingMessage{
@@ -241,20 +311,40 @@ func moveCase(gameID string, playerID string, msg types.IncomingMessage, client
 			Data: data,
 		}
 		BroadcastToGame(gameID, response)
-
+		ScheduleBotMove(gameID, store, config)
 	}
 
 	return false
 }
 
-func placeCase(gameID string, playerID string, msg types.IncomingMessage, client *mongo.Client, config config.Config) {
+func legalMovesCase(gameID string, playerID string, store store.Store, config config.Config) {
+	game, moves, err := engine.LegalMovesCase(gameID, playerID, store)
+	if err != nil {
+		broadcastError(gameID, playerID, err)
+		return
+	}
+
+	data := types.LegalMovesResponse{
+		ID:    game.ID,
+		Turn:  game.Turn,
+		Moves: moves,
+	}
+
+	response := types.OutgoingMessage{
+		Type: "legalMoves",
+		Data: data,
+	}
+	BroadcastToPlayer(gameID, playerID, response)
+}
+
+func placeCase(gameID string, playerID string, msg types.IncomingMessage, store store.Store, config config.Config) {
 	postPlace, err := utils.ParseMsgJSON[types.PostPlace](msg)
 	if err != nil {
 		broadcastError(gameID, playerID, err)
 		return
 	}
 
-	_, data, err := engine.PlaceCase(gameID, playerID, postPlace, client, config)
+	_, data, err := engine.PlaceCase(gameID, playerID, postPlace, store)
 	if err != nil {
 		broadcastError(gameID, playerID, err)
 		return
@@ -268,22 +358,47 @@ func placeCase(gameID string, playerID string, msg types.IncomingMessage, client
 
 }
 
-func readyCase(gameID string, playerID string, msg types.IncomingMessage, client *mongo.Client, config config.Config) bool {
+// proposals only go to the player asking
+func armyCase(gameID string, playerID string, msg types.IncomingMessage, store store.Store, config config.Config) {
+	postArmy, err := utils.ParseMsgJSON[types.PostArmy](msg)
+	if err != nil {
+		broadcastError(gameID, playerID, err)
+		return
+	}
+
+	_, data, err := engine.ArmyCase(gameID, playerID, postArmy, store)
+	if err != nil {
+		broadcastError(gameID, playerID, err)
+		return
+	}
+
+	response := types.OutgoingMessage{
+		Type: "army",
+		Data: data,
+	}
+	if data.Placed {
+		BroadcastToGame(gameID, response)
+	} else {
+		BroadcastToPlayer(gameID, playerID, response)
+	}
+}
+
+func readyCase(gameID string, playerID string, msg types.IncomingMessage, store store.Store, config config.Config) bool {
 	postReady, err := utils.ParseMsgJSON[types.PostReady](msg)
 	if err != nil {
 		broadcastError(gameID, playerID, err)
 		return false
 	}
 
-	game, fen, err := engine.ReadyCase(gameID, playerID, postReady, client, config)
+	game, fen, err := engine.ReadyCase(gameID, playerID, postReady, store)
 	if err != nil {
 		broadcastError(gameID, playerID, err)
 		return false
 	}
 
 	if game.State == types.MoveState {
-		gameLog := engine.SetupGameLog(*game)
-		_, err := db.CreateGameLog(client, config.DB, gameLog)
+		gameLog := engine.SetupGameLog(*game, fen)
+		_, err := store.CreateGameLog(gameLog)
 		if err != nil {
 			broadcastError(gameID, playerID, err)
 			return false
@@ -294,6 +409,7 @@ func readyCase(gameID string, playerID string, msg types.IncomingMessage, client
 			FEN:   fen,
 			Ready: game.Ready,
 			State: game.State,
+			Clock: engine.GetClock(*game),
 		}
 
 		response := types.OutgoingMessage{
@@ -301,22 +417,24 @@ func readyCase(gameID string, playerID string, msg types.IncomingMessage, client
 			Data: data,
 		}
 		BroadcastToGame(gameID, response)
+		ScheduleBotMove(gameID, store, config)
 
 	} else if game.State == types.OverState {
-		gameLog := engine.SetupGameLog(*game)
-		_, err := db.CreateGameLog(client, config.DB, gameLog)
+		gameLog := engine.SetupGameLog(*game, fen)
+		_, err := store.CreateGameLog(gameLog)
 		if err != nil {
 			broadcastError(gameID, playerID, err)
 			return false
 		}
 
-		return GameOver(game, gameID, playerID, client, config)
+		return GameOver(game, gameID, playerID, store, config)
 	} else {
 		data := types.ReadyResponse{
 			ID:    game.ID,
 			FEN:   fen,
 			Ready: game.Ready,
 			State: game.State,
+			Clock: engine.GetClock(*game),
 		}
 
 		response := types.OutgoingMessage{
@@ -328,26 +446,27 @@ func readyCase(gameID string, playerID string, msg types.IncomingMessage, client
 	return false
 }
 
-func drawCase(gameID string, playerID string, msg types.IncomingMessage, client *mongo.Client, config config.Config) bool {
+func drawCase(gameID string, playerID string, msg types.IncomingMessage, store store.Store, config config.Config) bool {
 	postDraw, err := utils.ParseMsgJSON[types.PostDrawRequest](msg)
 	if err != nil {
 		broadcastError(gameID, playerID, err)
 		return false
 	}
 
-	game, err := engine.DrawCase(gameID, playerID, postDraw, client, config)
+	game, err := engine.DrawCase(gameID, playerID, postDraw, store)
 	if err != nil {
 		broadcastError(gameID, playerID, err)
 		return false
 	}
 
 	if game.State == types.OverState {
-		return GameOver(game, gameID, playerID, client, config)
+		return GameOver(game, gameID, playerID, store, config)
 
 	} else {
 		data := map[string]interface{}{
-			"_id":  game.ID,
-			"draw": game.Draw,
+			"_id":   game.ID,
+			"draw":  game.Draw,
+			"clock": engine.GetClock(*game),
 		}
 
 		response := types.OutgoingMessage{
@@ -360,85 +479,55 @@ func drawCase(gameID string, playerID string, msg types.IncomingMessage, client
 	return false
 }
 
-func resignCase(gameID string, playerID string, client *mongo.Client, config config.Config) bool {
-	game, err := engine.ResignCase(gameID, playerID, client, config)
+func takebackCase(gameID string, playerID string, msg types.IncomingMessage, store store.Store, config config.Config) {
+	postTakeback, err := utils.ParseMsgJSON[types.PostTakebackRequest](msg)
 	if err != nil {
 		broadcastError(gameID, playerID, err)
-		return false
-	}
-
-	if game.State == types.OverState {
-		return GameOver(game, gameID, playerID, client, config)
+		return
 	}
 
-	return false
-}
-
-func GameOver(game *types.Game, gameID string, playerID string, client *mongo.Client, config config.Config) bool {
-	gameLog, err := db.FindGameLogFromGameID(client, config.DB, gameID)
+	_, data, err := engine.TakebackCase(gameID, playerID, postTakeback, store)
 	if err != nil {
 		broadcastError(gameID, playerID, err)
-		return false
+		return
 	}
 
-	fen, err := engine.ConvertBoardToString(*game)
-	if err != nil {
-		broadcastError(gameID, playerID, err)
-		return false
+	response := types.OutgoingMessage{
+		Type: "takeback",
+		Data: data,
 	}
+	BroadcastToGame(gameID, response)
+	ScheduleBotMove(gameID, store, config)
+}
 
-	engine.SetupFinalGameLog(*game, gameLog)
-	err = db.GameLogFinalUpdate(client, config.DB, gameID, *gameLog)
+func resignCase(gameID string, playerID string, store store.Store, config config.Config) bool {
+	game, err := engine.ResignCase(gameID, playerID, store)
 	if err != nil {
 		broadcastError(gameID, playerID, err)
 		return false
 	}
 
-	whiteWon := 0
-	blackWon := 0
-	if *game.Winner == 0 {
-		whiteWon = 1
-	} else if *game.Winner == 1 {
-		blackWon = 1
-	}
-
-	whiteStats, err := db.FindUserStatsFromUserID(client, config.DB, game.WhiteID)
-	if err != nil {
-		broadcastError(gameID, playerID, err)
-		return false
+	if game.State == types.OverState {
+		return GameOver(game, gameID, playerID, store, config)
 	}
 
-	whiteStatsUpdate := types.UpdateUserStats{
-		GamesPlayed: whiteStats.GamesPlayed + 1,
-		GamesWon:    whiteStats.GamesPlayed + whiteWon,
-		GameLog:     gameLog.ID.Hex(),
-	}
+	return false
+}
 
-	err = db.UpdateUserStats(client, config.DB, game.WhiteID, whiteStatsUpdate)
-	if err != nil {
-		broadcastError(gameID, playerID, err)
-		return false
-	}
+func GameOver(game *types.Game, gameID string, playerID string, store store.Store, config config.Config) bool {
+	engine.CancelClock(gameID)
 
-	blackStats, err := db.FindUserStatsFromUserID(client, config.DB, game.BlackID)
+	err := engine.GameOverCase(*game, gameID, store)
 	if err != nil {
 		broadcastError(gameID, playerID, err)
 		return false
 	}
 
-	blackStatsUpdate := types.UpdateUserStats{
-		GamesPlayed: blackStats.GamesPlayed + 1,
-		GamesWon:    blackStats.GamesPlayed + blackWon,
-		GameLog:     gameLog.ID.Hex(),
-	}
-
-	err = db.UpdateUserStats(client, config.DB, game.BlackID, blackStatsUpdate)
-	if err != nil {
-		broadcastError(gameID, playerID, err)
-		return false
-	}
+	return broadcastGameOver(game, gameID, playerID)
+}
 
-	_, err = db.DeleteGame(client, config.DB, gameID)
+func broadcastGameOver(game *types.Game, gameID string, playerID string) bool {
+	fen, err := engine.ConvertBoardToString(*game)
 	if err != nil {
 		broadcastError(gameID, playerID, err)
 		return false
@@ -455,6 +544,7 @@ func GameOver(game *types.Game, gameID string, playerID string, client *mongo.Cl
 		State:         game.State,
 		LastMoveTime:  game.LastMoveTime,
 		FEN:           fen,
+		Clock:         engine.GetClock(*game),
 	}
 
 	response := types.OutgoingMessage{
@@ -285,7 +285,7 @@ function getPawnMoves(pos: Vec2, piece: Piece, game: Game, direction: number): V
     newPos.y += pos.y
     if (checkPositionInbounds(newPos, game)) {
       const space = game.board[newPos.y][newPos.x]
-      if (space !== null && space.owner == piece.owner) {
+      if (space !== null && space.owner != piece.owner) {
         result.push(newPos)
       } else if (space === null && game.enPassant !== null && checkVec2Equal(newPos, game.enPassant)) {
         result.push(newPos)